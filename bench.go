@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/logger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// benchRow is timing of one problem over all runs
+type benchRow struct {
+	fileName  string
+	name      string
+	algorithm string
+	answer    string
+	nodes     int
+	runs      []time.Duration
+	err       string
+}
+
+func (row benchRow) minMean() (time.Duration, time.Duration) {
+	if len(row.runs) == 0 {
+		return 0, 0
+	}
+	min, total := row.runs[0], time.Duration(0)
+	for _, run := range row.runs {
+		if run < min {
+			min = run
+		}
+		total += run
+	}
+	return min, total / time.Duration(len(row.runs))
+}
+
+func runBench(args []string) int {
+	cmd, _ := findCommand(BENCH)
+	flagSet := newFlagSet(cmd)
+	inputs := addInputFlags(flagSet)
+	getOptions := addSolverFlags(flagSet)
+	repeat := flagSet.Int("repeat", 3, "number of runs of every problem")
+	if code, ok := parseFlags(flagSet, args); !ok {
+		return code
+	}
+	options := getOptions()
+	if !isFlagSet(flagSet, "output_directory") {
+		tempDir, err := ioutil.TempDir("", "matiasevich_bench")
+		if err != nil {
+			logger.Errorf("error creating temporary directory: %v", err)
+			return 1
+		}
+		defer os.RemoveAll(tempDir)
+		options.OutputDir = tempDir
+	}
+	files, err := inputs.files()
+	if err != nil {
+		logger.Errorf("error reading input: %v", err)
+		return 1
+	}
+	var rows []benchRow
+	code := 0
+	for _, fileName := range files {
+		inputFile, err := open(fileName)
+		if err != nil {
+			rows = append(rows, benchRow{fileName: fileName, err: err.Error()})
+			code = 1
+			continue
+		}
+		problems, _, err := readProblems(inputFile, fileName, *inputs.inputFormat)
+		inputFile.Close()
+		if err != nil {
+			rows = append(rows, benchRow{fileName: fileName, err: fmt.Sprintf("error reading input: %v", err)})
+			code = 1
+			continue
+		}
+		for _, problem := range problems {
+			row := benchRow{fileName: fileName, name: problem.Name, algorithm: problem.Algorithm}
+			for i := 0; i < *repeat; i++ {
+				result := solveProblem(context.Background(), problem, fileName, options, false, false)
+				if result.Error != "" {
+					row.err = result.Error
+					code = 1
+					break
+				}
+				row.answer, row.nodes = result.Answer, result.NodesCount
+				row.runs = append(row.runs, time.Duration(result.DurationNs))
+			}
+			rows = append(rows, row)
+		}
+	}
+	printBenchTable(rows)
+	return code
+}
+
+func printBenchTable(rows []benchRow) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "file\tname\talgorithm\tanswer\tnodes\tmin\tmean\t")
+	var total time.Duration
+	for _, row := range rows {
+		min, mean := row.minMean()
+		total += mean
+		answer := row.answer
+		if row.err != "" {
+			answer = "ERROR"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t\n", filepath.Base(row.fileName), row.name, row.algorithm,
+			answer, row.nodes, min, mean)
+	}
+	fmt.Fprintf(writer, "total\t\t\t\t\t\t%s\t\n", total)
+	writer.Flush()
+	for _, row := range rows {
+		if row.err != "" {
+			fmt.Printf("%s %s: %s\n", row.fileName, row.name, strings.TrimSpace(row.err))
+		}
+	}
+}
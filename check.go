@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/logger"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/input"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	CHECK_OK        = "ok"
+	CHECK_UNCHECKED = "unchecked"
+	CHECK_ERROR     = "ERROR"
+	CHECK_TIMEOUT   = "NEW TIMEOUT"
+	CHECK_MISMATCH  = "MISMATCH"
+	CHECK_CHANGED   = "CHANGED"
+	CHECK_SLOWER    = "SLOWER"
+)
+
+// baselineProblem is the stored result of one problem, which later runs are compared to
+type baselineProblem struct {
+	File       string `json:"file"`
+	Name       string `json:"name,omitempty"`
+	Line       int    `json:"line"`
+	Algorithm  string `json:"algorithm"`
+	Answer     string `json:"answer"`
+	DurationNs int64  `json:"duration_ns"`
+	NodesCount int    `json:"nodes_count"`
+}
+
+type baseline struct {
+	Problems []baselineProblem `json:"problems"`
+}
+
+// problemKey identifies problem by file name and problem name, or by line for problems without name
+func problemKey(fileName string, name string, line int) string {
+	if name != "" {
+		return filepath.Base(fileName) + "\x00" + name
+	}
+	return filepath.Base(fileName) + "\x00" + strconv.Itoa(line)
+}
+
+func readBaseline(fileName string) (map[string]baselineProblem, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening baseline: %v", err)
+	}
+	defer file.Close()
+	var stored baseline
+	err = json.NewDecoder(file).Decode(&stored)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding baseline: %v", err)
+	}
+	problems := map[string]baselineProblem{}
+	for _, problem := range stored.Problems {
+		problems[problemKey(problem.File, problem.Name, problem.Line)] = problem
+	}
+	return problems, nil
+}
+
+func writeBaseline(fileName string, results []processResult) error {
+	stored := baseline{Problems: []baselineProblem{}}
+	for _, result := range results {
+		if result.Error != "" {
+			continue
+		}
+		stored.Problems = append(stored.Problems, baselineProblem{
+			File:       filepath.Base(result.FileName),
+			Name:       result.Name,
+			Line:       result.Line,
+			Algorithm:  result.Algorithm,
+			Answer:     result.Answer,
+			DurationNs: result.DurationNs,
+			NodesCount: result.NodesCount,
+		})
+	}
+	file, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("error creating baseline: %v", err)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(stored)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error writing baseline: %v", err)
+	}
+	return file.Close()
+}
+
+// checker compares results to expected answers and to the baseline
+type checker struct {
+	baseline    map[string]baselineProblem
+	slowdown    float64
+	minSlowdown time.Duration
+}
+
+// status returns the check status of result and the baseline problem it was compared to
+func (checker checker) status(result processResult) (string, *baselineProblem) {
+	if result.Error != "" {
+		return CHECK_ERROR, nil
+	}
+	var stored *baselineProblem
+	if problem, ok := checker.baseline[problemKey(result.FileName, result.Name, result.Line)]; ok {
+		stored = &problem
+	}
+	if result.Answer == solver.TIMEOUT && (stored == nil || stored.Answer != solver.TIMEOUT) {
+		return CHECK_TIMEOUT, stored
+	}
+	if result.Expected != "" && result.Answer != result.Expected {
+		return CHECK_MISMATCH, stored
+	}
+	if stored == nil {
+		if result.Expected == "" {
+			return CHECK_UNCHECKED, nil
+		}
+		return CHECK_OK, nil
+	}
+	if result.Answer != stored.Answer {
+		return CHECK_CHANGED, stored
+	}
+	duration, storedDuration := time.Duration(result.DurationNs), time.Duration(stored.DurationNs)
+	if float64(duration) > checker.slowdown*float64(storedDuration) && duration-storedDuration > checker.minSlowdown {
+		return CHECK_SLOWER, stored
+	}
+	return CHECK_OK, stored
+}
+
+// checkFile solves problems of the file, expected answers missing in the file are taken from manifest,
+// timeout limits the time of every problem
+func checkFile(fileName string, inputFormat string, options solver.Options, manifest input.Manifest,
+	timeout time.Duration) []processResult {
+	inputFile, err := open(fileName)
+	if err != nil {
+		logger.Errorf("%v", err)
+		return []processResult{{FileName: fileName, Error: err.Error()}}
+	}
+	problems, _, err := readProblems(inputFile, fileName, inputFormat)
+	inputFile.Close()
+	if err != nil {
+		logger.Errorf("error reading input: %v", err)
+		return []processResult{{FileName: fileName, Error: fmt.Sprintf("error reading input: %v", err)}}
+	}
+	var results []processResult
+	for _, problem := range problems {
+		if problem.Expect == "" {
+			problem.Expect, _ = manifest.Expect(filepath.Base(fileName), problem.Name)
+		}
+		ctx, cancel := context.Background(), context.CancelFunc(func() {})
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		results = append(results, solveProblem(ctx, problem, fileName, options, false, false))
+		cancel()
+	}
+	return results
+}
+
+func runCheck(args []string) int {
+	cmd, _ := findCommand(CHECK)
+	flagSet := newFlagSet(cmd)
+	inputs := addInputFlags(flagSet)
+	getOptions := addSolverFlags(flagSet)
+	manifestFile := flagSet.String("manifest", "", "file with expected answers, "+
+		input.MANIFEST_FILENAME+" of the input directory by default")
+	baselineFile := flagSet.String("baseline", "", "JSON file with results of a previous run to compare to")
+	newBaselineFile := flagSet.String("write_baseline", "", "JSON file to write results of this run to")
+	timeout := flagSet.Duration("timeout", 10*time.Second, "maximum time of one problem, 0 for no limit")
+	jobs := flagSet.Int("jobs", 1, "number of input files solved at once, timings are less stable with more jobs")
+	slowdown := flagSet.Float64("slowdown", 2, "problems solved this many times slower than in the baseline are reported")
+	minSlowdown := flagSet.Duration("min_slowdown", 10*time.Millisecond,
+		"slowdowns smaller than this duration are not reported")
+	if code, ok := parseFlags(flagSet, args); !ok {
+		return code
+	}
+	if *jobs < 1 {
+		fmt.Fprintln(flagSet.Output(), "jobs should be positive")
+		return 2
+	}
+	options := getOptions()
+	if !isFlagSet(flagSet, "output_directory") {
+		options.DiscardGraph = true
+	}
+	if *manifestFile == "" && *inputs.inputDir != "" {
+		*manifestFile = filepath.Join(*inputs.inputDir, input.MANIFEST_FILENAME)
+		if _, err := os.Stat(*manifestFile); err != nil {
+			*manifestFile = ""
+		}
+	}
+	var manifest input.Manifest
+	if *manifestFile != "" {
+		file, err := os.Open(*manifestFile)
+		if err != nil {
+			logger.Errorf("error opening manifest: %v", err)
+			return 1
+		}
+		manifest, err = input.ReadManifest(file)
+		file.Close()
+		if err != nil {
+			logger.Errorf("error reading manifest: %v", err)
+			return 1
+		}
+	}
+	check := checker{slowdown: *slowdown, minSlowdown: *minSlowdown}
+	if *baselineFile != "" {
+		var err error
+		check.baseline, err = readBaseline(*baselineFile)
+		if err != nil {
+			logger.Errorf("%v", err)
+			return 1
+		}
+	}
+	files, err := inputs.files()
+	if err != nil {
+		logger.Errorf("error reading input: %v", err)
+		return 1
+	}
+	// manifest and baselines may be kept in the input directory, they are not inputs
+	skip := map[string]bool{}
+	for _, fileName := range []string{*manifestFile, *baselineFile, *newBaselineFile} {
+		if fileName != "" {
+			skip[filepath.Clean(fileName)] = true
+		}
+	}
+	var inputFiles []string
+	for _, fileName := range files {
+		if !skip[filepath.Clean(fileName)] {
+			inputFiles = append(inputFiles, fileName)
+		}
+	}
+	var results []processResult
+	solveFiles(inputFiles, *jobs, func(fileName string) []processResult {
+		return checkFile(fileName, *inputs.inputFormat, options, manifest, *timeout)
+	}, func(fileResults []processResult) {
+		results = append(results, fileResults...)
+	})
+	code := printCheckTable(results, check)
+	if *newBaselineFile != "" {
+		err = writeBaseline(*newBaselineFile, results)
+		if err != nil {
+			logger.Errorf("%v", err)
+			return 1
+		}
+	}
+	return code
+}
+
+// printCheckTable prints problems which failed the check and counts of statuses,
+// it returns exit code 1 if some problem failed
+func printCheckTable(results []processResult, check checker) int {
+	counts := map[string]int{}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "status\tfile\tname\tline\texpected\tanswer\tbaseline\ttime\tbaseline time\t")
+	for _, result := range results {
+		status, stored := check.status(result)
+		counts[status]++
+		if status == CHECK_OK || status == CHECK_UNCHECKED {
+			continue
+		}
+		storedAnswer, storedDuration := "", ""
+		if stored != nil {
+			storedAnswer, storedDuration = stored.Answer, time.Duration(stored.DurationNs).String()
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t\n", status, filepath.Base(result.FileName),
+			result.Name, result.Line, result.Expected, result.Answer, storedAnswer, time.Duration(result.DurationNs),
+			storedDuration)
+	}
+	writer.Flush()
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Printf("%s %s: %s\n", result.FileName, result.Name, strings.TrimSpace(result.Error))
+		}
+	}
+	fmt.Printf("checked %d problems:", len(results))
+	for _, status := range []string{CHECK_OK, CHECK_UNCHECKED, CHECK_MISMATCH, CHECK_CHANGED, CHECK_TIMEOUT,
+		CHECK_SLOWER, CHECK_ERROR} {
+		fmt.Printf(" %s %d", status, counts[status])
+		if status != CHECK_ERROR {
+			fmt.Printf(",")
+		}
+	}
+	fmt.Println()
+	if counts[CHECK_OK]+counts[CHECK_UNCHECKED] != len(results) {
+		return 1
+	}
+	return 0
+}
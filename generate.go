@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/logger"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/generator"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/input"
+	"os"
+	"strings"
+	"time"
+)
+
+// splitLetters splits comma-separated letters
+func splitLetters(letters string) []string {
+	var result []string
+	for _, letter := range strings.Split(letters, ",") {
+		if letter = strings.TrimSpace(letter); letter != "" {
+			result = append(result, letter)
+		}
+	}
+	return result
+}
+
+func runGenerate(args []string) int {
+	cmd, _ := findCommand(GENERATE)
+	flagSet := newFlagSet(cmd)
+	constants := flagSet.String("constants", "a,b", "comma-separated constants")
+	variables := flagSet.String("variables", "x,y", "comma-separated variables")
+	count := flagSet.Int("count", 10, "number of equations")
+	maxLength := flagSet.Int("max_length", 5, "maximum number of symbols on one side of equation")
+	minLength := flagSet.Int("min_length", 1, "minimum number of symbols on one side of equation")
+	variablesCount := flagSet.Int("variables_count", 0, "number of distinct variables in every equation, 0 for any")
+	shape := flagSet.String("shape", generator.ANY, "equation shape: any, quadratic (every variable occurs "+
+		"at most twice) or nonquadratic (some variable occurs at least three times)")
+	satisfiable := flagSet.Bool("satisfiable", false, "generate equations with a solution, "+
+		"built by substituting a random assignment")
+	maxValueLength := flagSet.Int("max_value_length", 2, "maximum length of variable values of satisfiable equations")
+	seed := flagSet.Int64("seed", 0, "random seed, 0 for the current time")
+	algorithm := flagSet.String("algorithm", "Standard", "algorithm type of generated problems")
+	outputFile := flagSet.String("output_file", "", "output filename, stdout if not set")
+	if code, ok := parseFlags(flagSet, args); !ok {
+		return code
+	}
+	if *satisfiable && *maxValueLength == 0 {
+		fmt.Fprintln(flagSet.Output(), "max_value_length should be positive")
+		return 2
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	gen, err := generator.New(generator.Options{
+		Constants:      splitLetters(*constants),
+		Variables:      splitLetters(*variables),
+		MaxLength:      *maxLength,
+		MinLength:      *minLength,
+		VariablesCount: *variablesCount,
+		Shape:          *shape,
+		Satisfiable:    *satisfiable,
+		MaxValueLength: *maxValueLength,
+		Seed:           *seed,
+	})
+	if err != nil {
+		logger.Errorf("error creating generator: %v", err)
+		fmt.Fprintf(os.Stderr, "error creating generator: %v\n", err)
+		return 2
+	}
+	var problems []input.Problem
+	expect := ""
+	if *satisfiable {
+		expect = input.TRUE
+	}
+	for i := 0; i < *count; i++ {
+		equation, err := gen.Next()
+		if err != nil {
+			logger.Errorf("error generating equation: %v", err)
+			fmt.Fprintf(os.Stderr, "error generating equation: %v\n", err)
+			return 1
+		}
+		problems = append(problems, input.Problem{
+			Name:      fmt.Sprintf("generated_%d_%d", *seed, i+1),
+			Algorithm: *algorithm,
+			Constants: equation.ConstantsString(),
+			Variables: equation.VariablesString(),
+			Equation:  equation.String(),
+			Expect:    expect,
+		})
+	}
+	output := os.Stdout
+	if *outputFile != "" {
+		output, err = os.Create(*outputFile)
+		if err != nil {
+			logger.Errorf("error creating output file: %v", err)
+			fmt.Fprintf(os.Stderr, "error creating output file: %v\n", err)
+			return 1
+		}
+		defer output.Close()
+	}
+	err = input.Write(output, problems)
+	if err != nil {
+		logger.Errorf("error writing problems: %v", err)
+		fmt.Fprintf(os.Stderr, "error writing problems: %v\n", err)
+		return 1
+	}
+	return 0
+}
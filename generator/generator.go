@@ -0,0 +1,386 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const emptySymbol = "$"
+
+const (
+	ANY           = "any"
+	QUADRATIC     = "quadratic"
+	NON_QUADRATIC = "nonquadratic"
+)
+
+const (
+	defaultMaxValueLength = 2
+	// maxAttempts is the number of candidates tried to build satisfiable equation with sides of allowed length
+	maxAttempts = 1000
+)
+
+// Options describes equations to generate
+type Options struct {
+	Constants []string
+	Variables []string
+	// MaxLength is the maximum number of symbols on one side of equation
+	MaxLength int
+	// MinLength is the minimum number of symbols on one side of equation, 1 if not set
+	MinLength int
+	// VariablesCount is the number of distinct variables occurring in every equation, 0 for any number
+	VariablesCount int
+	// Shape is ANY, QUADRATIC, where every variable occurs at most twice, or NON_QUADRATIC,
+	// where some variable occurs at least three times; ANY if not set
+	Shape string
+	// Satisfiable makes equations have a solution: right side is built from the value of left side
+	// under a random assignment, which is kept in the equation
+	Satisfiable bool
+	// MaxValueLength is the maximum length of variable values of satisfiable equations, 2 if not set
+	MaxValueLength int
+	Seed           int64
+}
+
+// Equation is a generated equation, sides are lists of letters
+type Equation struct {
+	Constants []string
+	Variables []string
+	Left      []string
+	Right     []string
+	// Solution is the assignment satisfiable equation was built from, values are lists of constants
+	Solution map[string][]string
+}
+
+func formatSide(side []string) string {
+	if len(side) == 0 {
+		return emptySymbol
+	}
+	return strings.Join(side, " ")
+}
+
+func formatAlphabet(letters []string) string {
+	return fmt.Sprintf("{%s}", strings.Join(letters, ", "))
+}
+
+func (equation Equation) String() string {
+	return fmt.Sprintf("%s = %s", formatSide(equation.Left), formatSide(equation.Right))
+}
+
+// ConstantsString returns constants alphabet in the input format
+func (equation Equation) ConstantsString() string {
+	return formatAlphabet(equation.Constants)
+}
+
+// VariablesString returns variables alphabet in the input format
+func (equation Equation) VariablesString() string {
+	return formatAlphabet(equation.Variables)
+}
+
+// SolutionString returns the solution in the assignment format, such as x = a b, y = $
+func (equation Equation) SolutionString() string {
+	var values []string
+	for _, variable := range equation.Variables {
+		if value, ok := equation.Solution[variable]; ok {
+			values = append(values, fmt.Sprintf("%s = %s", variable, formatSide(value)))
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// IsQuadratic checks that every variable occurs in equation at most twice
+func (equation Equation) IsQuadratic() bool {
+	for _, count := range equation.variableCounts() {
+		if count > 2 {
+			return false
+		}
+	}
+	return true
+}
+
+func (equation Equation) variableCounts() map[string]int {
+	isVariable := map[string]bool{}
+	for _, variable := range equation.Variables {
+		isVariable[variable] = true
+	}
+	counts := map[string]int{}
+	for _, side := range [][]string{equation.Left, equation.Right} {
+		for _, letter := range side {
+			if isVariable[letter] {
+				counts[letter]++
+			}
+		}
+	}
+	return counts
+}
+
+// Generator produces random equations, the same seed gives the same equations
+type Generator struct {
+	options Options
+	random  *rand.Rand
+	// minTotal and maxTotal limit the number of symbols of both sides
+	minTotal int
+	maxTotal int
+}
+
+func New(options Options) (*Generator, error) {
+	if len(options.Constants)+len(options.Variables) == 0 {
+		return nil, fmt.Errorf("no letters to generate equations from")
+	}
+	if options.MinLength == 0 {
+		options.MinLength = 1
+	}
+	if options.MaxLength < 1 {
+		return nil, fmt.Errorf("invalid max length: %d", options.MaxLength)
+	}
+	if options.MinLength < 1 || options.MinLength > options.MaxLength {
+		return nil, fmt.Errorf("invalid min length: %d", options.MinLength)
+	}
+	if options.VariablesCount < 0 || options.VariablesCount > len(options.Variables) {
+		return nil, fmt.Errorf("invalid variables count: %d, there are %d variables",
+			options.VariablesCount, len(options.Variables))
+	}
+	if options.Shape == "" {
+		options.Shape = ANY
+	}
+	if options.MaxValueLength == 0 {
+		options.MaxValueLength = defaultMaxValueLength
+	}
+	if options.MaxValueLength < 0 {
+		return nil, fmt.Errorf("invalid max value length: %d", options.MaxValueLength)
+	}
+	if options.Satisfiable && len(options.Constants) == 0 {
+		// values of variables are empty, so the right side built from the value of the left one is empty too
+		return nil, fmt.Errorf("can't build satisfiable equation of min length %d without constants",
+			options.MinLength)
+	}
+	generator := &Generator{
+		options:  options,
+		random:   rand.New(rand.NewSource(options.Seed)),
+		minTotal: 2 * options.MinLength,
+		maxTotal: 2 * options.MaxLength,
+	}
+	if options.Satisfiable {
+		// only the left side is built from letters, the right side is built from its value
+		generator.minTotal, generator.maxTotal = options.MinLength, options.MaxLength
+	}
+	variablesCount := options.VariablesCount
+	if variablesCount == 0 {
+		variablesCount = len(options.Variables)
+	}
+	if options.VariablesCount > 0 && options.VariablesCount > generator.maxTotal {
+		return nil, fmt.Errorf("%d variables don't fit in equation of max length %d",
+			options.VariablesCount, options.MaxLength)
+	}
+	switch options.Shape {
+	case ANY:
+	case QUADRATIC:
+		if len(options.Constants) == 0 && generator.minTotal > 2*variablesCount {
+			return nil, fmt.Errorf("can't build quadratic equation of min length %d from %d variables",
+				options.MinLength, variablesCount)
+		}
+	case NON_QUADRATIC:
+		required := 3
+		if options.VariablesCount > 1 {
+			required += options.VariablesCount - 1
+		}
+		if variablesCount == 0 || generator.maxTotal < required {
+			return nil, fmt.Errorf("can't build non-quadratic equation of max length %d with %d variables",
+				options.MaxLength, variablesCount)
+		}
+	default:
+		return nil, fmt.Errorf("invalid shape: %s", options.Shape)
+	}
+	return generator, nil
+}
+
+// chooseVariables returns variables which may occur in the next equation
+// and the ones which must occur in it
+func (generator *Generator) chooseVariables() ([]string, []string) {
+	variables := generator.options.Variables
+	if generator.options.VariablesCount == 0 {
+		return variables, nil
+	}
+	chosen := make([]string, generator.options.VariablesCount)
+	for i, index := range generator.random.Perm(len(variables))[:len(chosen)] {
+		chosen[i] = variables[index]
+	}
+	return chosen, chosen
+}
+
+// letters returns random letters of the equation: every mandatory variable occurs once,
+// for NON_QUADRATIC shape some variable occurs three times, for QUADRATIC every variable occurs at most twice
+func (generator *Generator) letters(variables []string, mandatory []string) []string {
+	letters := append([]string{}, mandatory...)
+	counts := map[string]int{}
+	for _, letter := range mandatory {
+		counts[letter]++
+	}
+	if generator.options.Shape == NON_QUADRATIC {
+		repeated := variables[generator.random.Intn(len(variables))]
+		for counts[repeated] < 3 {
+			letters = append(letters, repeated)
+			counts[repeated]++
+		}
+	}
+	minTotal, maxTotal := generator.minTotal, generator.maxTotal
+	if generator.options.Shape == QUADRATIC && len(generator.options.Constants) == 0 && maxTotal > 2*len(variables) {
+		maxTotal = 2 * len(variables)
+	}
+	if minTotal < len(letters) {
+		minTotal = len(letters)
+	}
+	total := minTotal + generator.random.Intn(maxTotal-minTotal+1)
+	for len(letters) < total {
+		candidates := append([]string{}, generator.options.Constants...)
+		for _, variable := range variables {
+			if generator.options.Shape != QUADRATIC || counts[variable] < 2 {
+				candidates = append(candidates, variable)
+			}
+		}
+		letter := candidates[generator.random.Intn(len(candidates))]
+		letters = append(letters, letter)
+		counts[letter]++
+	}
+	generator.random.Shuffle(len(letters), func(i, j int) {
+		letters[i], letters[j] = letters[j], letters[i]
+	})
+	return letters
+}
+
+// split splits letters into two sides of allowed length
+func (generator *Generator) split(letters []string) ([]string, []string) {
+	minLeft, maxLeft := len(letters)-generator.options.MaxLength, len(letters)-generator.options.MinLength
+	if minLeft < generator.options.MinLength {
+		minLeft = generator.options.MinLength
+	}
+	if maxLeft > generator.options.MaxLength {
+		maxLeft = generator.options.MaxLength
+	}
+	left := minLeft + generator.random.Intn(maxLeft-minLeft+1)
+	return letters[:left], letters[left:]
+}
+
+// assignment returns random values of variables
+func (generator *Generator) assignment(variables []string) map[string][]string {
+	solution := map[string][]string{}
+	for _, variable := range variables {
+		value := []string{}
+		if len(generator.options.Constants) > 0 {
+			value = make([]string, generator.random.Intn(generator.options.MaxValueLength+1))
+			for i := range value {
+				value[i] = generator.options.Constants[generator.random.Intn(len(generator.options.Constants))]
+			}
+		}
+		solution[variable] = value
+	}
+	return solution
+}
+
+// rightSide splits the value of the left side into constants and values of variables,
+// for QUADRATIC shape variables occurring twice in the left side are not used
+func (generator *Generator) rightSide(left []string, solution map[string][]string) []string {
+	counts := map[string]int{}
+	var value []string
+	for _, letter := range left {
+		if variableValue, ok := solution[letter]; ok {
+			counts[letter]++
+			value = append(value, variableValue...)
+			continue
+		}
+		value = append(value, letter)
+	}
+	allowed := func(variable string) bool {
+		return generator.options.Shape != QUADRATIC || counts[variable] < 2
+	}
+	var right []string
+	for position := 0; position <= len(value); {
+		var empty, matching []string
+		for _, variable := range generator.options.Variables {
+			variableValue, ok := solution[variable]
+			if !ok || !allowed(variable) {
+				continue
+			}
+			if len(variableValue) == 0 {
+				empty = append(empty, variable)
+			} else if hasPrefix(value[position:], variableValue) {
+				matching = append(matching, variable)
+			}
+		}
+		if len(empty) > 0 && generator.random.Intn(4) == 0 {
+			variable := empty[generator.random.Intn(len(empty))]
+			right = append(right, variable)
+			counts[variable]++
+			continue
+		}
+		if position == len(value) {
+			break
+		}
+		if len(matching) > 0 && generator.random.Intn(3) > 0 {
+			variable := matching[generator.random.Intn(len(matching))]
+			right = append(right, variable)
+			counts[variable]++
+			position += len(solution[variable])
+			continue
+		}
+		right = append(right, value[position])
+		position++
+	}
+	return right
+}
+
+func hasPrefix(word []string, prefix []string) bool {
+	if len(prefix) > len(word) {
+		return false
+	}
+	for i := range prefix {
+		if word[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// satisfiable returns equation built from a random assignment, candidates are built until the right side
+// has allowed length, at most maxAttempts times
+func (generator *Generator) satisfiable() (Equation, error) {
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		variables, mandatory := generator.chooseVariables()
+		left := generator.letters(variables, mandatory)
+		solution := generator.assignment(variables)
+		right := generator.rightSide(left, solution)
+		if generator.random.Intn(2) == 0 {
+			left, right = right, left
+		}
+		equation := Equation{
+			Constants: generator.options.Constants,
+			Variables: generator.options.Variables,
+			Left:      left,
+			Right:     right,
+			Solution:  map[string][]string{},
+		}
+		for variable := range equation.variableCounts() {
+			equation.Solution[variable] = solution[variable]
+		}
+		if len(left) >= generator.options.MinLength && len(left) <= generator.options.MaxLength &&
+			len(right) >= generator.options.MinLength && len(right) <= generator.options.MaxLength {
+			return equation, nil
+		}
+	}
+	return Equation{}, fmt.Errorf("no satisfiable equation with sides of length %d to %d found in %d attempts",
+		generator.options.MinLength, generator.options.MaxLength, maxAttempts)
+}
+
+// Next returns the next random equation, it fails if satisfiable equation of allowed length wasn't found
+func (generator *Generator) Next() (Equation, error) {
+	if generator.options.Satisfiable {
+		return generator.satisfiable()
+	}
+	variables, mandatory := generator.chooseVariables()
+	left, right := generator.split(generator.letters(variables, mandatory))
+	return Equation{
+		Constants: generator.options.Constants,
+		Variables: generator.options.Variables,
+		Left:      left,
+		Right:     right,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Next_1(t *testing.T) {
+	options := Options{Constants: []string{"a", "b"}, Variables: []string{"x", "y"}, MaxLength: 4, Seed: 7}
+	first, err := New(options)
+	if err != nil {
+		t.Errorf("Test_Next_1 error should be nil: %v", err)
+		return
+	}
+	second, _ := New(options)
+	for i := 0; i < 20; i++ {
+		firstEquation, err := first.Next()
+		if err != nil {
+			t.Errorf("Test_Next_1 error should be nil: %v", err)
+			return
+		}
+		secondEquation, _ := second.Next()
+		if firstEquation.String() != secondEquation.String() {
+			t.Errorf("Test_Next_1 failed: same seed gave %s and %s", firstEquation, secondEquation)
+		}
+		for _, side := range [][]string{firstEquation.Left, firstEquation.Right} {
+			if len(side) < 1 || len(side) > options.MaxLength {
+				t.Errorf("Test_Next_1 failed: wrong side length: %s", firstEquation)
+			}
+		}
+	}
+}
+
+func Test_New_Error_1(t *testing.T) {
+	tests := []struct {
+		options Options
+		err     string
+	}{
+		{Options{MaxLength: 3}, "no letters to generate equations from"},
+		{Options{Variables: []string{"x"}, MaxLength: 3, VariablesCount: 2}, "invalid variables count: 2, there are 1 variables"},
+		{Options{Variables: []string{"x"}, MaxLength: 3, MinLength: 2, Shape: QUADRATIC},
+			"can't build quadratic equation of min length 2 from 1 variables"},
+		{Options{Constants: []string{"a"}, MaxLength: 3, Shape: NON_QUADRATIC},
+			"can't build non-quadratic equation of max length 3 with 0 variables"},
+		{Options{Constants: []string{"a"}, MaxLength: 3, Shape: "linear"}, "invalid shape: linear"},
+		{Options{Variables: []string{"x", "y"}, MaxLength: 4, MinLength: 3, Satisfiable: true},
+			"can't build satisfiable equation of min length 3 without constants"},
+	}
+	for _, test := range tests {
+		_, err := New(test.options)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("Test_New_Error_1 failed: expected error: %s, but got: %v", test.err, err)
+		}
+	}
+}
+
+func substitute(side []string, solution map[string][]string) string {
+	var value []string
+	for _, letter := range side {
+		if variableValue, ok := solution[letter]; ok {
+			value = append(value, variableValue...)
+			continue
+		}
+		value = append(value, letter)
+	}
+	return strings.Join(value, " ")
+}
+
+func Test_Next_Shape_1(t *testing.T) {
+	tests := []Options{
+		{Constants: []string{"a", "b"}, Variables: []string{"x", "y", "z"}, MaxLength: 6, VariablesCount: 2, Shape: QUADRATIC},
+		{Constants: []string{"a"}, Variables: []string{"x", "y"}, MaxLength: 5, MinLength: 3, Shape: NON_QUADRATIC},
+		{Variables: []string{"x", "y"}, MaxLength: 4, Shape: QUADRATIC},
+	}
+	for i, options := range tests {
+		options.Seed = int64(i + 1)
+		gen, err := New(options)
+		if err != nil {
+			t.Errorf("Test_Next_Shape_1 error should be nil: %v", err)
+			continue
+		}
+		for j := 0; j < 50; j++ {
+			equation, err := gen.Next()
+			if err != nil {
+				t.Errorf("Test_Next_Shape_1 error should be nil: %v", err)
+				continue
+			}
+			if equation.IsQuadratic() != (options.Shape == QUADRATIC) {
+				t.Errorf("Test_Next_Shape_1 failed: equation %s is not %s", equation, options.Shape)
+			}
+			if options.VariablesCount > 0 && len(equation.variableCounts()) != options.VariablesCount {
+				t.Errorf("Test_Next_Shape_1 failed: equation %s should have %d variables", equation,
+					options.VariablesCount)
+			}
+			minLength := options.MinLength
+			if minLength == 0 {
+				minLength = 1
+			}
+			for _, side := range [][]string{equation.Left, equation.Right} {
+				if len(side) < minLength || len(side) > options.MaxLength {
+					t.Errorf("Test_Next_Shape_1 failed: wrong side length: %s", equation)
+				}
+			}
+		}
+	}
+}
+
+func Test_Next_Satisfiable_1(t *testing.T) {
+	for _, shape := range []string{ANY, QUADRATIC, NON_QUADRATIC} {
+		options := Options{Constants: []string{"a", "b"}, Variables: []string{"x", "y", "z"}, MaxLength: 6,
+			MinLength: 3, VariablesCount: 2, Shape: shape, Satisfiable: true, MaxValueLength: 3, Seed: 3}
+		gen, err := New(options)
+		if err != nil {
+			t.Errorf("Test_Next_Satisfiable_1 error should be nil: %v", err)
+			continue
+		}
+		for i := 0; i < 50; i++ {
+			equation, err := gen.Next()
+			if err != nil {
+				t.Errorf("Test_Next_Satisfiable_1 error should be nil: %v", err)
+				continue
+			}
+			for _, side := range [][]string{equation.Left, equation.Right} {
+				if len(side) < options.MinLength || len(side) > options.MaxLength {
+					t.Errorf("Test_Next_Satisfiable_1 failed: wrong side length: %s", equation)
+				}
+			}
+			left, right := substitute(equation.Left, equation.Solution), substitute(equation.Right, equation.Solution)
+			if left != right {
+				t.Errorf("Test_Next_Satisfiable_1 failed: %s is not solved by %s: %s != %s", equation,
+					equation.SolutionString(), left, right)
+			}
+			if shape == QUADRATIC && !equation.IsQuadratic() || shape == NON_QUADRATIC && equation.IsQuadratic() {
+				t.Errorf("Test_Next_Satisfiable_1 failed: equation %s is not %s", equation, shape)
+			}
+		}
+	}
+}
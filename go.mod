@@ -1,8 +1,15 @@
 module github.com/saskamegaprogrammist/MatiasevichWESolver
 
-go 1.15
+go 1.18
 
 require (
 	github.com/goccy/go-graphviz v0.0.9
 	github.com/google/logger v1.1.0
 )
+
+require (
+	github.com/fogleman/gg v1.3.0 // indirect
+	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/image v0.0.0-20200119044424-58c23975cae1 // indirect
+)
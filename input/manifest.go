@@ -0,0 +1,77 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	MANIFEST_FILENAME = "expected.tsv"
+	MANIFEST_SEP      = "\t"
+)
+
+// Manifest keeps expected answers of problems given apart from input files,
+// so answers of files which can't have expect key, such as SMT-LIB scripts, are recorded too
+type Manifest struct {
+	// files maps file name to expected answer of all its problems
+	files map[string]string
+	// problems maps file name and problem name to expected answer of the problem
+	problems map[string]map[string]string
+}
+
+// Expect returns expected answer of the problem of file, answer given for the problem name
+// takes precedence over the answer given for the whole file
+func (manifest Manifest) Expect(fileName string, problemName string) (string, bool) {
+	if answer, ok := manifest.problems[fileName][problemName]; ok && problemName != "" {
+		return answer, true
+	}
+	answer, ok := manifest.files[fileName]
+	return answer, ok
+}
+
+// ReadManifest reads tab-separated lines 'file	answer' or 'file	problem name	answer',
+// empty lines and lines starting with # are skipped
+func ReadManifest(reader io.Reader) (Manifest, error) {
+	manifest := Manifest{files: map[string]string{}, problems: map[string]map[string]string{}}
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, COMMENT) {
+			continue
+		}
+		fields := strings.Split(line, MANIFEST_SEP)
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if len(fields) != 2 && len(fields) != 3 {
+			return manifest, fmt.Errorf("line %d: expected 2 or 3 tab-separated fields, but got: %d",
+				lineNumber, len(fields))
+		}
+		fileName, answer := fields[0], fields[len(fields)-1]
+		if answer != TRUE && answer != FALSE && answer != CYCLED {
+			return manifest, fmt.Errorf("line %d: invalid expected answer: %s", lineNumber, answer)
+		}
+		if len(fields) == 2 {
+			if _, ok := manifest.files[fileName]; ok {
+				return manifest, fmt.Errorf("line %d: duplicate file: %s", lineNumber, fileName)
+			}
+			manifest.files[fileName] = answer
+			continue
+		}
+		if manifest.problems[fileName] == nil {
+			manifest.problems[fileName] = map[string]string{}
+		}
+		if _, ok := manifest.problems[fileName][fields[1]]; ok {
+			return manifest, fmt.Errorf("line %d: duplicate problem: %s", lineNumber, fields[1])
+		}
+		manifest.problems[fileName][fields[1]] = answer
+	}
+	if err := scanner.Err(); err != nil {
+		return manifest, fmt.Errorf("scanner error: %v", err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,56 @@
+package input
+
+import (
+	"strings"
+	"testing"
+)
+
+var testManifest = "# expected answers\n" +
+	"1.txt\tTRUE\n" +
+	"\n" +
+	"problems.txt\tno solutions\tFALSE\n" +
+	"problems.txt\tCYCLED\n"
+
+func Test_ReadManifest_1(t *testing.T) {
+	manifest, err := ReadManifest(strings.NewReader(testManifest))
+	if err != nil {
+		t.Errorf("Test_ReadManifest_1 error should be nil: %v", err)
+		return
+	}
+	tests := []struct {
+		fileName string
+		name     string
+		answer   string
+		ok       bool
+	}{
+		{"1.txt", "", TRUE, true},
+		{"1.txt", "commutation", TRUE, true},
+		{"problems.txt", "no solutions", FALSE, true},
+		{"problems.txt", "other", CYCLED, true},
+		{"2.txt", "", "", false},
+	}
+	for _, test := range tests {
+		answer, ok := manifest.Expect(test.fileName, test.name)
+		if answer != test.answer || ok != test.ok {
+			t.Errorf("Test_ReadManifest_1 failed: %s %s: expected: %s %v, but got: %s %v",
+				test.fileName, test.name, test.answer, test.ok, answer, ok)
+		}
+	}
+}
+
+func Test_ReadManifest_Error_1(t *testing.T) {
+	tests := []struct {
+		manifest string
+		err      string
+	}{
+		{"1.txt TRUE\n", "line 1: expected 2 or 3 tab-separated fields, but got: 1"},
+		{"1.txt\tSAT\n", "line 1: invalid expected answer: SAT"},
+		{"1.txt\tTRUE\n1.txt\tFALSE\n", "line 2: duplicate file: 1.txt"},
+	}
+	for _, test := range tests {
+		_, err := ReadManifest(strings.NewReader(test.manifest))
+		if err == nil || err.Error() != test.err {
+			t.Errorf("Test_ReadManifest_Error_1 failed: expected error: %s, but got: %v", test.err, err)
+		}
+	}
+}
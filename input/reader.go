@@ -0,0 +1,193 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	VERSION     = 2
+	COMMENT     = "#"
+	KEY_SEP     = ":"
+	VERSION_KEY = "version"
+	NAME        = "name"
+	ALGORITHM   = "algorithm"
+	CONSTANTS   = "constants"
+	VARIABLES   = "variables"
+	EQUATION    = "equation"
+	EXPECT      = "expect"
+	CYCLE_RANGE = "cycle_range"
+)
+
+const (
+	TRUE   = "TRUE"
+	FALSE  = "FALSE"
+	CYCLED = "CYCLED"
+)
+
+const defaultAlgorithm = "Standard"
+
+// positionalKeys are filled in order by lines without a key, as in the first version of the format
+var positionalKeys = []string{ALGORITHM, CONSTANTS, VARIABLES, EQUATION}
+
+var problemKeys = map[string]bool{
+	NAME:        true,
+	ALGORITHM:   true,
+	CONSTANTS:   true,
+	VARIABLES:   true,
+	EQUATION:    true,
+	EXPECT:      true,
+	CYCLE_RANGE: true,
+}
+
+// Problem is one equation description read from input
+type Problem struct {
+	Name       string
+	Algorithm  string
+	Constants  string
+	Variables  string
+	Equation   string
+	Expect     string
+	CycleRange int
+	// Implicit is set when constants or variables are not given, so they have to be inferred from the equation
+	Implicit bool
+	// Line is the number of the first line of the problem
+	Line int
+	// Lines maps keys to the numbers of lines they were read from
+	Lines map[string]int
+	// Err is set when the problem description is invalid
+	Err error
+}
+
+func (problem *Problem) set(key string, value string, line int) error {
+	if _, ok := problem.Lines[key]; ok {
+		return fmt.Errorf("line %d: duplicate %s, first given at line %d", line, key, problem.Lines[key])
+	}
+	problem.Lines[key] = line
+	switch key {
+	case NAME:
+		problem.Name = value
+	case ALGORITHM:
+		problem.Algorithm = value
+	case CONSTANTS:
+		problem.Constants = value
+	case VARIABLES:
+		problem.Variables = value
+	case EQUATION:
+		problem.Equation = value
+	case EXPECT:
+		if value != TRUE && value != FALSE && value != CYCLED {
+			return fmt.Errorf("line %d: invalid expected answer: %s", line, value)
+		}
+		problem.Expect = value
+	case CYCLE_RANGE:
+		cycleRange, err := strconv.Atoi(value)
+		if err != nil || cycleRange < 0 {
+			return fmt.Errorf("line %d: invalid cycle range: %s", line, value)
+		}
+		problem.CycleRange = cycleRange
+	}
+	return nil
+}
+
+func (problem *Problem) setPositional(value string, line int) error {
+	for _, key := range positionalKeys {
+		if _, ok := problem.Lines[key]; !ok {
+			return problem.set(key, value, line)
+		}
+	}
+	return fmt.Errorf("line %d: unexpected line without key: %s", line, value)
+}
+
+func (problem *Problem) check() error {
+	if problem.Algorithm == "" {
+		problem.Algorithm = defaultAlgorithm
+	}
+	if _, ok := problem.Lines[EQUATION]; !ok {
+		return fmt.Errorf("line %d: missing %s", problem.Line, EQUATION)
+	}
+	for _, key := range []string{CONSTANTS, VARIABLES} {
+		if _, ok := problem.Lines[key]; !ok {
+			problem.Implicit = true
+		}
+	}
+	return nil
+}
+
+// splitKey returns key and value of the line if it starts with one of known keys
+func splitKey(line string) (string, string, bool) {
+	index := strings.Index(line, KEY_SEP)
+	if index < 0 {
+		return "", "", false
+	}
+	key := strings.TrimSpace(line[:index])
+	if !problemKeys[key] && key != VERSION_KEY {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(line[index+1:]), true
+}
+
+// Read reads problems separated by blank lines. Lines starting with # are comments,
+// lines of the form key: value set problem fields, lines without key set algorithm type,
+// constants, variables and equation in this order, so the first version of the format,
+// which is exactly these four lines, is read as one problem
+func Read(reader io.Reader) ([]Problem, error) {
+	var problems []Problem
+	var current *Problem
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	versionAllowed := true
+	finish := func() {
+		if current != nil {
+			if current.Err == nil {
+				current.Err = current.check()
+			}
+			problems = append(problems, *current)
+			current = nil
+		}
+	}
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			finish()
+			continue
+		}
+		if strings.HasPrefix(trimmed, COMMENT) {
+			continue
+		}
+		key, value, isKey := splitKey(trimmed)
+		if isKey && key == VERSION_KEY {
+			if !versionAllowed {
+				return problems, fmt.Errorf("line %d: version must be given before problems", lineNumber)
+			}
+			version, err := strconv.Atoi(value)
+			if err != nil || version < 1 || version > VERSION {
+				return problems, fmt.Errorf("line %d: unsupported version: %s", lineNumber, value)
+			}
+			versionAllowed = false
+			continue
+		}
+		versionAllowed = false
+		if current == nil {
+			current = &Problem{Line: lineNumber, Lines: map[string]int{}}
+		}
+		if current.Err != nil {
+			continue
+		}
+		if isKey {
+			current.Err = current.set(key, value, lineNumber)
+		} else {
+			current.Err = current.setPositional(trimmed, lineNumber)
+		}
+	}
+	finish()
+	if err := scanner.Err(); err != nil {
+		return problems, fmt.Errorf("scanner error: %v", err)
+	}
+	return problems, nil
+}
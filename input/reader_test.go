@@ -0,0 +1,91 @@
+package input
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Read_FirstVersion_1(t *testing.T) {
+	problems, err := Read(strings.NewReader("Finite\n{a, b}\n{u}\nu u a = b u u\n"))
+	if err != nil {
+		t.Errorf("Test_Read_FirstVersion_1 error should be nil: %v", err)
+		return
+	}
+	if len(problems) != 1 {
+		t.Errorf("Test_Read_FirstVersion_1 failed: expected 1 problem, but got: %d", len(problems))
+		return
+	}
+	problem := problems[0]
+	if problem.Err != nil || problem.Algorithm != "Finite" || problem.Constants != "{a, b}" ||
+		problem.Variables != "{u}" || problem.Equation != "u u a = b u u" {
+		t.Errorf("Test_Read_FirstVersion_1 failed: wrong problem: %+v", problem)
+	}
+}
+
+var testMultipleProblems = `version: 2
+# first problem
+name: commutation
+constants: {a}
+variables: {x, y}
+equation: x y = y x
+expect: TRUE
+cycle_range: 30
+
+
+# second problem in the first version order
+Finite
+{a}
+{u}
+a u = u
+expect: FALSE
+
+name: implicit
+equation: X a = a X
+
+name: broken
+constants: {a}
+`
+
+func Test_Read_1(t *testing.T) {
+	problems, err := Read(strings.NewReader(testMultipleProblems))
+	if err != nil {
+		t.Errorf("Test_Read_1 error should be nil: %v", err)
+		return
+	}
+	if len(problems) != 4 {
+		t.Errorf("Test_Read_1 failed: expected 4 problems, but got: %d", len(problems))
+		return
+	}
+	first := problems[0]
+	if first.Err != nil || first.Name != "commutation" || first.Algorithm != defaultAlgorithm ||
+		first.Equation != "x y = y x" || first.Expect != TRUE || first.CycleRange != 30 || first.Line != 3 {
+		t.Errorf("Test_Read_1 failed: wrong first problem: %+v", first)
+	}
+	second := problems[1]
+	if second.Err != nil || second.Algorithm != "Finite" || second.Equation != "a u = u" ||
+		second.Expect != FALSE || second.Lines[EQUATION] != 15 {
+		t.Errorf("Test_Read_1 failed: wrong second problem: %+v", second)
+	}
+	if first.Implicit || second.Implicit {
+		t.Errorf("Test_Read_1 failed: alphabets of the first and the second problems are given")
+	}
+	third := problems[2]
+	if third.Err != nil || !third.Implicit || third.Equation != "X a = a X" {
+		t.Errorf("Test_Read_1 failed: wrong third problem: %+v", third)
+	}
+	fourth := problems[3]
+	if fourth.Err == nil || fourth.Err.Error() != "line 21: missing equation" {
+		t.Errorf("Test_Read_1 failed: wrong fourth problem error: %v", fourth.Err)
+	}
+}
+
+func Test_Read_Error_1(t *testing.T) {
+	_, err := Read(strings.NewReader("version: 3\n"))
+	if err == nil || err.Error() != "line 1: unsupported version: 3" {
+		t.Errorf("Test_Read_Error_1 failed: wrong error: %v", err)
+	}
+	problems, _ := Read(strings.NewReader("expect: MAYBE\n"))
+	if len(problems) != 1 || problems[0].Err == nil || problems[0].Err.Error() != "line 1: invalid expected answer: MAYBE" {
+		t.Errorf("Test_Read_Error_1 failed: wrong problem error")
+	}
+}
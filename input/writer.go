@@ -0,0 +1,40 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Write writes problems in the second version of the format, so they can be read back by Read
+func Write(writer io.Writer, problems []Problem) error {
+	bufWriter := bufio.NewWriter(writer)
+	fmt.Fprintf(bufWriter, "%s%s %d\n", VERSION_KEY, KEY_SEP, VERSION)
+	for _, problem := range problems {
+		fmt.Fprintln(bufWriter)
+		fields := []struct {
+			key   string
+			value string
+		}{
+			{NAME, problem.Name},
+			{ALGORITHM, problem.Algorithm},
+			{CONSTANTS, problem.Constants},
+			{VARIABLES, problem.Variables},
+			{EQUATION, problem.Equation},
+			{EXPECT, problem.Expect},
+		}
+		for _, field := range fields {
+			if field.value != "" {
+				fmt.Fprintf(bufWriter, "%s%s %s\n", field.key, KEY_SEP, field.value)
+			}
+		}
+		if problem.CycleRange != 0 {
+			fmt.Fprintf(bufWriter, "%s%s %d\n", CYCLE_RANGE, KEY_SEP, problem.CycleRange)
+		}
+	}
+	err := bufWriter.Flush()
+	if err != nil {
+		return fmt.Errorf("error writing problems: %v", err)
+	}
+	return nil
+}
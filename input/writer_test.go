@@ -0,0 +1,33 @@
+package input
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_Write_1(t *testing.T) {
+	problems := []Problem{
+		{Name: "first", Algorithm: "Finite", Constants: "{a}", Variables: "{x}", Equation: "x a = a x", Expect: TRUE},
+		{Constants: "{a, b}", Variables: "{u}", Equation: "u a = b u", CycleRange: 30},
+	}
+	var buffer bytes.Buffer
+	err := Write(&buffer, problems)
+	if err != nil {
+		t.Errorf("Test_Write_1 error should be nil: %v", err)
+		return
+	}
+	read, err := Read(&buffer)
+	if err != nil || len(read) != 2 {
+		t.Errorf("Test_Write_1 failed: problems should be read back: %v", err)
+		return
+	}
+	for i, problem := range read {
+		if problem.Err != nil || problem.Equation != problems[i].Equation || problem.Constants != problems[i].Constants ||
+			problem.Expect != problems[i].Expect || problem.CycleRange != problems[i].CycleRange {
+			t.Errorf("Test_Write_1 failed: wrong problem read back: %+v", problem)
+		}
+	}
+	if read[1].Algorithm != defaultAlgorithm {
+		t.Errorf("Test_Write_1 failed: default algorithm should be set: %s", read[1].Algorithm)
+	}
+}
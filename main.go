@@ -1,113 +1,106 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
-	"github.com/google/logger"
 	matlog "github.com/saskamegaprogrammist/MatiasevichWESolver/logger"
-	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver"
 	"os"
-	"sort"
+	"strings"
 )
 
-func handleScannerError(scanner *bufio.Scanner) error {
-	var scannerErr error
-	if err := scanner.Err(); err != nil {
-		scannerErr = fmt.Errorf("scanner error: %v", err)
-		logger.Errorf(scannerErr.Error())
-	}
-	return scannerErr
-}
+const (
+	SOLVE    = "solve"
+	VERIFY   = "verify"
+	GENERATE = "generate"
+	BENCH    = "bench"
+	CHECK    = "check"
+	RENDER   = "render"
+	REPL     = "repl"
+	SERVE    = "serve"
+	HELP     = "help"
+)
 
-func scanInput(scanner *bufio.Scanner) error {
-	scanner.Scan()
-	return handleScannerError(scanner)
+// command is a subcommand of the program, run returns exit code
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(args []string) int
 }
 
-func parseFLags() (bool, string, string, int, bool, string) {
-	fullGraph := flag.Bool("full_graph", false, "print full graph")
-	inputFile := flag.String("input_file", "", "input filename")
-	inputDir := flag.String("input_directory", "", "input directory")
-	cycleRange := flag.Int("cycle_range", 0, "cycle depth")
-	makePng := flag.Bool("png", false, "create graph png")
-	outputDir := flag.String("output_directory", ".", "output directory")
-	flag.Parse()
-	return *fullGraph, *inputFile, *inputDir, *cycleRange, *makePng, *outputDir
-}
+var commands []command
 
-func process(inputSource *os.File, fullGraph bool, makePng bool, cycleRange int, outputDir string) {
-	var err error
-	scanner := bufio.NewScanner(inputSource)
-	err = handleScannerError(scanner)
-	if err != nil {
-		return
-	}
-	err = scanInput(scanner)
-	if err != nil {
-		return
-	}
-	algorithmType := scanner.Text()
-	err = scanInput(scanner)
-	if err != nil {
-		return
-	}
-	constantsAlph := scanner.Text()
-	err = scanInput(scanner)
-	if err != nil {
-		return
-	}
-	varsAlph := scanner.Text()
-	err = scanInput(scanner)
-	if err != nil {
-		return
+func init() {
+	commands = []command{
+		{SOLVE, "[flags]", "solve equations, this is the default command", runSolve},
+		{VERIFY, "-assignment 'x = a b, y = $' [flags]", "check that assignment is a solution of equations", runVerify},
+		{GENERATE, "[flags]", "generate random equations in the input format", runGenerate},
+		{BENCH, "[flags]", "solve a corpus of equations and tabulate timings", runBench},
+		{CHECK, "[flags]", "compare answers to expected ones and to a baseline of previous run", runCheck},
+		{REPL, "[flags]", "explore the search tree of an equation step by step", runREPL},
+		{SERVE, "[flags]", "serve POST /solve requests with JSON equation descriptions", runServe},
+		{RENDER, "[flags] file...", "render graph descriptions (.dot, .dot.gz, parts) or JSON trees to images", runRender},
 	}
-	equation := scanner.Text()
+}
 
-	var solver solver.Solver
-	err = solver.Init(algorithmType, constantsAlph, varsAlph, equation, fullGraph, makePng, cycleRange, outputDir)
-	if err != nil {
-		logger.Errorf("error initializing solver: %v", err)
-		return
-	}
-	hasSolution, measuredTime, err := solver.Solve()
-	if err != nil {
-		logger.Errorf("error writing graph: %v", err)
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags]\n\ncommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.description)
 	}
-	fmt.Printf("took time: %v \ngot solution: %s \n\n", measuredTime, hasSolution)
+	fmt.Fprintf(os.Stderr, "\nflags without a command are solve flags, run '%s <command> -h' for command flags\n", os.Args[0])
 }
 
-func main() {
-	matlog.LoggerSetup()
-	fullGraph, inputFilename, inputDirName, cycleRange, makePng, outputDir := parseFLags()
+// newFlagSet creates flag set of the command with usage text
+func newFlagSet(cmd command) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "usage: %s %s %s\n\n%s\n\nflags:\n", os.Args[0], cmd.name, cmd.usage, cmd.description)
+		flagSet.PrintDefaults()
+	}
+	return flagSet
+}
 
-	if inputDirName != "" {
-		inputDir, err := os.Open(inputDirName)
-		if err != nil {
-			logger.Errorf("error opening directory: %v", err)
-		}
-		files, err := inputDir.Readdir(-1)
-		inputDir.Close()
-		if err != nil {
-			logger.Errorf("error closing directory: %v", err)
+// isFlagSet checks whether flag was given in the command line
+func isFlagSet(flagSet *flag.FlagSet, name string) bool {
+	set := false
+	flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
 		}
+	})
+	return set
+}
 
-		sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() }) //sorting files by name
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
 
-		for _, file := range files {
-			inputFile, err := os.Open(fmt.Sprintf("%s%c%s", inputDirName, os.PathSeparator, file.Name()))
-			if err != nil {
-				logger.Errorf("error opening input file: %v", err)
+func main() {
+	matlog.LoggerSetup()
+	name, args := SOLVE, os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == HELP {
+		if len(args) > 0 {
+			if cmd, ok := findCommand(args[0]); ok {
+				os.Exit(cmd.run([]string{"-h"}))
 			}
-			process(inputFile, fullGraph, makePng, cycleRange, outputDir)
 		}
-	} else if inputFilename != "" {
-		inputFile, err := os.Open(inputFilename)
-		if err != nil {
-			logger.Errorf("error opening input file: %v", err)
-		}
-		process(inputFile, fullGraph, makePng, cycleRange, outputDir)
-	} else {
-		process(os.Stdin, fullGraph, makePng, cycleRange, outputDir)
+		printUsage()
+		return
+	}
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", name)
+		printUsage()
+		os.Exit(2)
 	}
+	os.Exit(cmd.run(args))
 }
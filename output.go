@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/smtlib"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	TEXT   = "text"
+	JSON   = "json"
+	NDJSON = "ndjson"
+)
+
+// processResult describes the result of processing one input
+type processResult struct {
+	FileName   string              `json:"file_name"`
+	Name       string              `json:"name,omitempty"`
+	Line       int                 `json:"line,omitempty"`
+	Equation   string              `json:"equation"`
+	Algorithm  string              `json:"algorithm"`
+	Answer     string              `json:"answer,omitempty"`
+	Expected   string              `json:"expected,omitempty"`
+	DurationNs int64               `json:"duration_ns"`
+	Duration   string              `json:"duration"`
+	NodesCount int                 `json:"nodes_count"`
+	MaxDepth   int                 `json:"max_depth"`
+	Solution   map[string][]string `json:"solution,omitempty"`
+	// Solutions describes all solutions of equation with one variable or two periodic variables
+	Solutions string `json:"solutions,omitempty"`
+	// PreCheck is the reason of FALSE answer found before the search
+	PreCheck   string            `json:"pre_check,omitempty"`
+	Statistics *statisticsResult `json:"statistics,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	// script is set for SMT-LIB input to print model with declared names
+	script *smtlib.Script
+}
+
+type statisticsResult struct {
+	RuleNodes         map[string]int `json:"rule_nodes"`
+	HasBeenHits       int            `json:"has_been_hits"`
+	InequalityLeaves  int            `json:"inequality_leaves"`
+	NoChildrenLeaves  int            `json:"no_children_leaves"`
+	CutNodes          int            `json:"cut_nodes"`
+	MaxEquationLength int            `json:"max_equation_length"`
+	FreshWords        int            `json:"fresh_words"`
+	SubstituteTimeNs  int64          `json:"substitute_time_ns"`
+	SamenessTimeNs    int64          `json:"sameness_time_ns"`
+	Quadratic         bool           `json:"quadratic,omitempty"`
+}
+
+func newStatisticsResult(statistics solver.Statistics) *statisticsResult {
+	return &statisticsResult{
+		RuleNodes:         statistics.GetRuleNodes(),
+		HasBeenHits:       statistics.HasBeenHits,
+		InequalityLeaves:  statistics.InequalityLeaves,
+		NoChildrenLeaves:  statistics.NoChildrenLeaves,
+		CutNodes:          statistics.CutNodes,
+		MaxEquationLength: statistics.MaxEquationLength,
+		FreshWords:        statistics.FreshWords,
+		SubstituteTimeNs:  statistics.SubstituteTime.Nanoseconds(),
+		SamenessTimeNs:    statistics.SamenessTime.Nanoseconds(),
+		Quadratic:         statistics.Quadratic,
+	}
+}
+
+// newProcessResult fills problem result with the solver result
+func newProcessResult(problemResult processResult, result solver.Result, withStatistics bool) processResult {
+	problemResult.Equation = strings.TrimSpace(result.Equation)
+	problemResult.Algorithm = result.Algorithm
+	problemResult.Answer = result.Answer
+	problemResult.DurationNs = result.Duration.Nanoseconds()
+	problemResult.Duration = result.Duration.String()
+	problemResult.NodesCount = result.NodesCount
+	problemResult.MaxDepth = result.MaxDepth
+	problemResult.Solution = result.Solution
+	if result.OneVariable != nil {
+		problemResult.Solutions = result.OneVariable.String()
+	}
+	if result.TwoVariables != nil {
+		problemResult.Solutions = result.TwoVariables.String()
+	}
+	problemResult.PreCheck = result.PreCheck
+	if withStatistics {
+		problemResult.Statistics = newStatisticsResult(result.Statistics)
+	}
+	return problemResult
+}
+
+// resultPrinter prints process results in the chosen output format,
+// json results are collected and printed as one array by Close
+type resultPrinter struct {
+	format     string
+	statistics bool
+	model      bool
+	results    []processResult
+}
+
+func newResultPrinter(format string, statistics bool, model bool) (*resultPrinter, error) {
+	switch format {
+	case TEXT, JSON, NDJSON, SMTLIB:
+		return &resultPrinter{format: format, statistics: statistics, model: model, results: []processResult{}}, nil
+	default:
+		return nil, fmt.Errorf("invalid output format: %s", format)
+	}
+}
+
+func (printer *resultPrinter) Print(result processResult) {
+	switch printer.format {
+	case TEXT:
+		if result.Answer == "" {
+			return
+		}
+		if result.Name != "" {
+			fmt.Printf("%s:\n", result.Name)
+		}
+		fmt.Printf("%s \n%s\n", result.Equation, result.Algorithm)
+		fmt.Printf("took time: %s \ngot solution: %s \n", result.Duration, result.Answer)
+		if result.Solution != nil {
+			fmt.Printf("solution: %s \n", formatSolution(result.Solution))
+		}
+		if result.Solutions != "" {
+			fmt.Printf("all solutions: %s \n", result.Solutions)
+		}
+		if result.PreCheck != "" {
+			fmt.Printf("pre-check: %s \n", result.PreCheck)
+		}
+		if result.Expected != "" && result.Expected != result.Answer {
+			fmt.Printf("expected: %s \n", result.Expected)
+		}
+		if result.Statistics != nil {
+			printStatistics(result)
+		}
+		fmt.Println()
+	case JSON:
+		printer.results = append(printer.results, result)
+	case NDJSON:
+		bytes, _ := json.Marshal(result)
+		fmt.Println(string(bytes))
+	case SMTLIB:
+		printSMTLIB(result, printer.model || result.script != nil && result.script.GetModel)
+	}
+}
+
+func (printer *resultPrinter) Close() {
+	if printer.format == JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(printer.results)
+	}
+}
+
+// formatSolution returns solution in the equation syntax, variables are sorted by name
+func formatSolution(solution map[string][]string) string {
+	var vars []string
+	for variable := range solution {
+		vars = append(vars, variable)
+	}
+	sort.Strings(vars)
+	var parts []string
+	for _, variable := range vars {
+		value := strings.Join(solution[variable], " ")
+		if value == "" {
+			value = "$"
+		}
+		parts = append(parts, fmt.Sprintf("%s = %s", variable, value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printStatistics(result processResult) {
+	statistics := result.Statistics
+	var rules []string
+	for rule := range statistics.RuleNodes {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	fmt.Printf("statistics:\n")
+	if statistics.Quadratic {
+		fmt.Printf("  quadratic equation, explored without cycle range\n")
+	}
+	fmt.Printf("  nodes: %d, max depth: %d, max equation length: %d\n",
+		result.NodesCount, result.MaxDepth, statistics.MaxEquationLength)
+	for _, rule := range rules {
+		fmt.Printf("  %s rule nodes: %d\n", rule, statistics.RuleNodes[rule])
+	}
+	fmt.Printf("  has been hits: %d\n", statistics.HasBeenHits)
+	fmt.Printf("  FALSE leaves: %d by inequality, %d with no children\n",
+		statistics.InequalityLeaves, statistics.NoChildrenLeaves)
+	fmt.Printf("  cut nodes: %d\n", statistics.CutNodes)
+	fmt.Printf("  fresh words: %d\n", statistics.FreshWords)
+	fmt.Printf("  substitute time: %v, sameness time: %v\n",
+		time.Duration(statistics.SubstituteTimeNs), time.Duration(statistics.SamenessTimeNs))
+}
+
+// printSMTLIB prints answer as check-sat response and model as get-model response,
+// errors are printed as SMT-LIB error responses
+func printSMTLIB(result processResult, withModel bool) {
+	if result.Answer == "" {
+		fmt.Printf("(error %s)\n", smtlib.QuoteString(result.Error))
+		return
+	}
+	answer := smtlib.Answer(result.Answer)
+	fmt.Println(answer)
+	if !withModel {
+		return
+	}
+	if answer != smtlib.SAT {
+		fmt.Printf("(error %s)\n", smtlib.QuoteString("model is not available"))
+		return
+	}
+	fmt.Println(smtlib.Model(result.Solution, result.script))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/logger"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// partPattern matches names of graph description parts, such as eq_graph_x.part2.dot.gz
+var partPattern = regexp.MustCompile(`^(.*)` + regexp.QuoteMeta(solver.PartSUFFIX) + `(\d+)` +
+	regexp.QuoteMeta(solver.GraphEXT) + `(` + regexp.QuoteMeta(solver.GzipEXT) + `)?$`)
+
+// graphParts returns all parts of the graph description the part file belongs to, ordered by number,
+// and the name of the graph without part suffix and extensions
+func graphParts(fileName string) ([]string, string, error) {
+	match := partPattern.FindStringSubmatch(fileName)
+	if match == nil {
+		base := strings.TrimSuffix(strings.TrimSuffix(fileName, solver.GzipEXT), solver.GraphEXT)
+		return []string{fileName}, base, nil
+	}
+	candidates, err := filepath.Glob(match[1] + solver.PartSUFFIX + "*")
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing parts: %v", err)
+	}
+	numbers := map[string]int{}
+	var parts []string
+	for _, candidate := range candidates {
+		candidateMatch := partPattern.FindStringSubmatch(candidate)
+		if candidateMatch == nil || candidateMatch[1] != match[1] {
+			continue
+		}
+		numbers[candidate], _ = strconv.Atoi(candidateMatch[2])
+		parts = append(parts, candidate)
+	}
+	sort.Slice(parts, func(i, j int) bool { return numbers[parts[i]] < numbers[parts[j]] })
+	for i, part := range parts {
+		if numbers[part] != i+1 {
+			return nil, "", fmt.Errorf("missing part %d of %s", i+1, match[1])
+		}
+	}
+	return parts, match[1], nil
+}
+
+// readGraph reads DOT description from graph file or all its parts, or builds it from JSON tree
+func readGraph(fileName string) ([]byte, string, error) {
+	if strings.HasSuffix(fileName, solver.TreeEXT) {
+		file, err := os.Open(fileName)
+		if err != nil {
+			return nil, "", fmt.Errorf("error opening tree file: %v", err)
+		}
+		defer file.Close()
+		tree, err := solver.ReadTree(file)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading tree file: %v", err)
+		}
+		return []byte(solver.TreeDOT(tree)), strings.TrimSuffix(fileName, filepath.Ext(fileName)), nil
+	}
+	parts, base, err := graphParts(fileName)
+	if err != nil {
+		return nil, "", err
+	}
+	var graph []byte
+	for _, part := range parts {
+		bytes, err := solver.ReadGraphFile(part)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading graph file: %v", err)
+		}
+		graph = append(graph, bytes...)
+	}
+	return graph, base, nil
+}
+
+func runRender(args []string) int {
+	cmd, _ := findCommand(RENDER)
+	flagSet := newFlagSet(cmd)
+	format := flagSet.String("format", solver.PNG, "image format: png or svg")
+	outputFile := flagSet.String("output_file", "", "image filename for a single input, "+
+		"by default the image is written next to the input")
+	if code, ok := parseFlags(flagSet, args); !ok {
+		return code
+	}
+	if flagSet.NArg() == 0 {
+		fmt.Fprintln(flagSet.Output(), "no files to render")
+		flagSet.Usage()
+		return 2
+	}
+	if *outputFile != "" && flagSet.NArg() > 1 {
+		fmt.Fprintln(flagSet.Output(), "output file can be set only for a single input")
+		return 2
+	}
+	code := 0
+	rendered := map[string]bool{}
+	for _, fileName := range flagSet.Args() {
+		graph, base, err := readGraph(fileName)
+		if err != nil {
+			logger.Errorf("error rendering %s: %v", fileName, err)
+			fmt.Fprintf(os.Stderr, "error rendering %s: %v\n", fileName, err)
+			code = 1
+			continue
+		}
+		imageFile := *outputFile
+		if imageFile == "" {
+			imageFile = fmt.Sprintf("%s.%s", base, *format)
+		}
+		if rendered[imageFile] {
+			continue
+		}
+		rendered[imageFile] = true
+		err = solver.RenderGraph(graph, *format, imageFile)
+		if err != nil {
+			logger.Errorf("error rendering %s: %v", fileName, err)
+			fmt.Fprintf(os.Stderr, "error rendering %s: %v\n", fileName, err)
+			code = 1
+			continue
+		}
+		fmt.Println(imageFile)
+	}
+	return code
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const replHelp = `commands:
+  constants {a, b}      set constants alphabet
+  variables {x, y}      set variables alphabet
+  algorithm Standard    set algorithm type: Standard or Finite
+  equation x a = a y    start exploring the tree of the equation
+  show                  show the current node, its leaf kind or applicable rule and children
+  child N               go to the N-th child of the current node
+  back                  go to the parent of the current node
+  root                  go to the root
+  path                  show substitutions on the path from the root to the current node
+  solve                 run automatic search from the current node
+  help                  show this help
+  quit                  exit`
+
+// replSession keeps the equation explored in REPL and the current node of its tree
+type replSession struct {
+	out       io.Writer
+	options   solver.Options
+	algorithm string
+	constants string
+	variables string
+	solver    *solver.Solver
+	current   *solver.Node
+}
+
+func (session *replSession) printf(format string, args ...interface{}) {
+	fmt.Fprintf(session.out, format, args...)
+}
+
+func (session *replSession) startEquation(equation string) {
+	var eqSolver solver.Solver
+	options := session.options
+	options.ImplicitAlphabets = options.ImplicitAlphabets || session.constants == "" || session.variables == ""
+	err := eqSolver.InitWithOptions(session.algorithm, session.constants, session.variables, equation, options)
+	if err != nil {
+		session.printf("error: %v\n", err)
+		return
+	}
+	session.solver = &eqSolver
+	session.current = eqSolver.Root()
+	session.show()
+}
+
+func (session *replSession) show() {
+	node := session.current
+	session.printf("node %s: %s\n", node.Number, strings.TrimSpace(node.Value.String()))
+	if node.Parent != nil {
+		session.printf("  from %s by %s rule", node.Parent.Number, solver.RuleName(node.Rule))
+		if node.Substitution != nil {
+			session.printf(": %s", node.Substitution)
+		}
+		session.printf("\n")
+	}
+	leaf, children := session.solver.Expand(node)
+	switch leaf {
+	case solver.CYCLED:
+		session.printf("  leaf CYCLED: repeats node %s\n", node.Repeats.Number)
+		return
+	case "":
+	default:
+		session.printf("  leaf %s\n", leaf)
+		return
+	}
+	session.printf("  %s rule applies:\n", solver.RuleName(children[0].Rule))
+	for i, child := range children {
+		substitution := ""
+		if child.Substitution != nil {
+			substitution = child.Substitution.String()
+		}
+		session.printf("  %d) [%s] %s : %s\n", i+1, solver.RuleTag(child.Rule), substitution,
+			strings.TrimSpace(child.Value.String()))
+	}
+}
+
+func (session *replSession) path() {
+	var nodes []*solver.Node
+	for node := session.current; node != nil; node = node.Parent {
+		nodes = append([]*solver.Node{node}, nodes...)
+	}
+	for _, node := range nodes {
+		if node.Parent == nil {
+			session.printf("%s: %s\n", node.Number, strings.TrimSpace(node.Value.String()))
+			continue
+		}
+		substitution := ""
+		if node.Substitution != nil {
+			substitution = " " + node.Substitution.String()
+		}
+		session.printf("  [%s]%s\n%s: %s\n", solver.RuleTag(node.Rule), substitution, node.Number,
+			strings.TrimSpace(node.Value.String()))
+	}
+}
+
+func (session *replSession) solve() {
+	result := session.solver.SolveFrom(session.current)
+	session.printf("got solution: %s, nodes: %d, max depth: %d, took time: %s\n",
+		result.Answer, result.NodesCount, result.MaxDepth, result.Duration)
+	if result.Solution != nil {
+		session.printf("solution: %s\n", formatSolution(result.Solution))
+	}
+}
+
+// execute runs one REPL command, it returns false to exit
+func (session *replSession) execute(line string) bool {
+	name, argument := line, ""
+	if index := strings.IndexAny(line, " \t"); index >= 0 {
+		name, argument = line[:index], strings.TrimSpace(line[index+1:])
+	}
+	switch name {
+	case "":
+	case "quit", "exit":
+		return false
+	case "help":
+		session.printf("%s\n", replHelp)
+	case "constants":
+		session.constants = argument
+	case "variables":
+		session.variables = argument
+	case "algorithm":
+		session.algorithm = argument
+	case "equation":
+		session.startEquation(argument)
+	case "show", "child", "back", "root", "path", "solve":
+		if session.solver == nil {
+			session.printf("error: no equation, set it with equation command\n")
+			return true
+		}
+		session.navigate(name, argument)
+	default:
+		session.printf("error: unknown command: %s, type help for the list of commands\n", name)
+	}
+	return true
+}
+
+func (session *replSession) navigate(name string, argument string) {
+	switch name {
+	case "show":
+		session.show()
+	case "child":
+		_, children := session.solver.Expand(session.current)
+		number, err := strconv.Atoi(argument)
+		if err != nil || number < 1 || number > len(children) {
+			session.printf("error: invalid child number: %s, node has %d children\n", argument, len(children))
+			return
+		}
+		session.current = children[number-1]
+		session.show()
+	case "back":
+		if session.current.Parent == nil {
+			session.printf("error: current node is the root\n")
+			return
+		}
+		session.current = session.current.Parent
+		session.show()
+	case "root":
+		session.current = session.solver.Root()
+		session.show()
+	case "path":
+		session.path()
+	case "solve":
+		session.solve()
+	}
+}
+
+func runREPL(args []string) int {
+	cmd, _ := findCommand(REPL)
+	flagSet := newFlagSet(cmd)
+	algorithm := flagSet.String("algorithm", "Standard", "algorithm type: Standard or Finite")
+	cycleRange := flagSet.Int("cycle_range", 0, "cycle depth of automatic search")
+	setParserOptions := addParserFlags(flagSet)
+	if code, ok := parseFlags(flagSet, args); !ok {
+		return code
+	}
+	session := replSession{
+		out:       os.Stdout,
+		options:   solver.Options{CycleRange: *cycleRange, DiscardGraph: true},
+		algorithm: *algorithm,
+	}
+	setParserOptions(&session.options)
+	session.printf("type help for the list of commands\n> ")
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if !session.execute(strings.TrimSpace(scanner.Text())) {
+			return 0
+		}
+		session.printf("> ")
+	}
+	session.printf("\n")
+	return 0
+}
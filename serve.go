@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/logger"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/input"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	DOT      = "dot"
+	maxBody  = 1 << 20
+	solveURL = "/solve"
+)
+
+// solveRequest is the body of solve request, alphabets may be omitted to be inferred from the equation
+type solveRequest struct {
+	Algorithm         string `json:"algorithm"`
+	Constants         string `json:"constants"`
+	Variables         string `json:"variables"`
+	Equation          string `json:"equation"`
+	CycleRange        int    `json:"cycle_range"`
+	FullGraph         bool   `json:"full_graph"`
+	ImplicitAlphabets bool   `json:"implicit_alphabets"`
+	VarPrefix         string `json:"var_prefix"`
+	Compact           bool   `json:"compact"`
+	GeneralSearch     bool   `json:"general_search"`
+	NoPreCheck        bool   `json:"no_precheck"`
+	// TimeoutMs limits the search time, it can't exceed server timeout
+	TimeoutMs int64 `json:"timeout_ms"`
+	// Graph is the format of the search graph to return: dot or svg, the graph is not returned if it's empty
+	Graph      string `json:"graph"`
+	Statistics bool   `json:"statistics"`
+}
+
+type solveResponse struct {
+	processResult
+	Graph string `json:"graph,omitempty"`
+}
+
+// solveServer solves equations of requests, at most cap(slots) at once
+type solveServer struct {
+	slots          chan struct{}
+	timeout        time.Duration
+	maxCycleRange  int
+	graphNodeLimit int
+}
+
+func (server *solveServer) writeJSON(writer http.ResponseWriter, status int, value interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	err := json.NewEncoder(writer).Encode(value)
+	if err != nil {
+		logger.Errorf("error writing response: %v", err)
+	}
+}
+
+func (server *solveServer) writeError(writer http.ResponseWriter, status int, format string, args ...interface{}) {
+	server.writeJSON(writer, status, processResult{Error: fmt.Sprintf(format, args...)})
+}
+
+func (server *solveServer) handleSolve(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.Header().Set("Allow", http.MethodPost)
+		server.writeError(writer, http.StatusMethodNotAllowed, "method not allowed: %s", request.Method)
+		return
+	}
+	var solveReq solveRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(writer, request.Body, maxBody))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&solveReq)
+	if err != nil {
+		server.writeError(writer, http.StatusBadRequest, "error decoding request: %v", err)
+		return
+	}
+	if solveReq.Graph != "" && solveReq.Graph != DOT && solveReq.Graph != solver.SVG {
+		server.writeError(writer, http.StatusBadRequest, "invalid graph format: %s", solveReq.Graph)
+		return
+	}
+	if solveReq.CycleRange < 0 || solveReq.CycleRange > server.maxCycleRange {
+		server.writeError(writer, http.StatusBadRequest, "cycle range should be from 0 to %d", server.maxCycleRange)
+		return
+	}
+	timeout := server.timeout
+	if solveReq.TimeoutMs > 0 && time.Duration(solveReq.TimeoutMs)*time.Millisecond < timeout {
+		timeout = time.Duration(solveReq.TimeoutMs) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(request.Context(), timeout)
+	defer cancel()
+	select {
+	case server.slots <- struct{}{}:
+		defer func() { <-server.slots }()
+	case <-ctx.Done():
+		server.writeError(writer, http.StatusServiceUnavailable, "server is busy")
+		return
+	}
+	response, status := server.solve(ctx, solveReq)
+	server.writeJSON(writer, status, response)
+}
+
+func (server *solveServer) solve(ctx context.Context, solveReq solveRequest) (solveResponse, int) {
+	problem := input.Problem{
+		Algorithm:  solveReq.Algorithm,
+		Constants:  solveReq.Constants,
+		Variables:  solveReq.Variables,
+		Equation:   solveReq.Equation,
+		CycleRange: solveReq.CycleRange,
+		Implicit:   solveReq.Constants == "" || solveReq.Variables == "",
+	}
+	if problem.Algorithm == "" {
+		problem.Algorithm = "Standard"
+	}
+	options := solver.Options{
+		FullGraph:            solveReq.FullGraph,
+		ImplicitAlphabets:    solveReq.ImplicitAlphabets,
+		VarPrefix:            solveReq.VarPrefix,
+		Compact:              solveReq.Compact,
+		GeneralSearch:        solveReq.GeneralSearch,
+		NoPreChecks:          solveReq.NoPreCheck,
+		DiscardGraph:         solveReq.Graph == "",
+		MemoryGraph:          solveReq.Graph != "",
+		MemoryGraphNodeLimit: server.graphNodeLimit,
+	}
+	response := solveResponse{processResult: processResult{Equation: problem.Equation, Algorithm: problem.Algorithm}}
+	var eqSolver solver.Solver
+	err := eqSolver.InitWithOptions(problem.Algorithm, problem.Constants, problem.Variables, problem.Equation,
+		problemOptions(problem, options))
+	if err != nil {
+		response.Error = fmt.Sprintf("error initializing solver: %v", err)
+		return response, http.StatusBadRequest
+	}
+	_, _, err = eqSolver.SolveContext(ctx)
+	response.processResult = newProcessResult(response.processResult, eqSolver.GetResult(), solveReq.Statistics)
+	if err != nil {
+		logger.Errorf("error writing graph: %v", err)
+		response.Error = fmt.Sprintf("error writing graph: %v", err)
+		return response, http.StatusInternalServerError
+	}
+	if solveReq.Graph == "" {
+		return response, http.StatusOK
+	}
+	if eqSolver.IsGraphDropped() {
+		response.Error = fmt.Sprintf("graph is not returned: graph has %d nodes, limit is %d",
+			response.NodesCount, server.graphNodeLimit)
+		return response, http.StatusOK
+	}
+	graph, err := eqSolver.GetGraph()
+	if err != nil {
+		response.Error = fmt.Sprintf("error reading graph: %v", err)
+		return response, http.StatusInternalServerError
+	}
+	if solveReq.Graph == DOT {
+		response.Graph = string(graph)
+		return response, http.StatusOK
+	}
+	var image strings.Builder
+	err = solver.RenderGraphTo(graph, solver.SVG, &image)
+	if err != nil {
+		logger.Errorf("error rendering graph: %v", err)
+		response.Error = fmt.Sprintf("error rendering graph: %v", err)
+		return response, http.StatusInternalServerError
+	}
+	response.Graph = image.String()
+	return response, http.StatusOK
+}
+
+func runServe(args []string) int {
+	cmd, _ := findCommand(SERVE)
+	flagSet := newFlagSet(cmd)
+	address := flagSet.String("address", "localhost:8080", "address to listen on")
+	concurrency := flagSet.Int("max_concurrent", runtime.NumCPU(), "maximum number of equations solved at once")
+	timeout := flagSet.Duration("timeout", 10*time.Second, "maximum time of one request, "+
+		"searches still running are stopped with TIMEOUT answer")
+	maxCycleRange := flagSet.Int("max_cycle_range", 1000, "maximum cycle range of a request")
+	graphNodeLimit := flagSet.Int("graph_node_limit", 5000, "graphs with more nodes are not returned, 0 for no limit")
+	if code, ok := parseFlags(flagSet, args); !ok {
+		return code
+	}
+	if *concurrency < 1 {
+		fmt.Fprintln(flagSet.Output(), "max_concurrent should be positive")
+		return 2
+	}
+	server := &solveServer{
+		slots:          make(chan struct{}, *concurrency),
+		timeout:        *timeout,
+		maxCycleRange:  *maxCycleRange,
+		graphNodeLimit: *graphNodeLimit,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(solveURL, server.handleSolve)
+	logger.Infof("listening on %s", *address)
+	fmt.Printf("listening on %s\n", *address)
+	err := http.ListenAndServe(*address, mux)
+	logger.Errorf("error serving: %v", err)
+	fmt.Printf("error serving: %v\n", err)
+	return 1
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func postSolve(server *solveServer, body string) (*httptest.ResponseRecorder, solveResponse) {
+	recorder := httptest.NewRecorder()
+	server.handleSolve(recorder, httptest.NewRequest(http.MethodPost, solveURL, bytes.NewBufferString(body)))
+	var response solveResponse
+	json.NewDecoder(recorder.Body).Decode(&response)
+	return recorder, response
+}
+
+func Test_Serve_GraphNodeLimit_1(t *testing.T) {
+	server := &solveServer{slots: make(chan struct{}, 1), timeout: 10 * time.Second, maxCycleRange: 100,
+		graphNodeLimit: 10}
+	body := `{"constants": "{a, b}", "variables": "{x, y, z}", "equation": "x a y z = z b y x", ` +
+		`"cycle_range": 10, "full_graph": true, "general_search": true, "no_precheck": true, "graph": "dot"}`
+	recorder, response := postSolve(server, body)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Test_Serve_GraphNodeLimit_1 failed: expected status: %d, but got: %d", http.StatusOK, recorder.Code)
+	}
+	if response.Graph != "" || !strings.HasPrefix(response.Error, "graph is not returned: graph has ") ||
+		!strings.HasSuffix(response.Error, "nodes, limit is 10") {
+		t.Errorf("Test_Serve_GraphNodeLimit_1 failed: graph should not be returned, but got error: %s", response.Error)
+	}
+	server.graphNodeLimit = 0
+	_, response = postSolve(server, body)
+	if response.Error != "" || !strings.HasPrefix(response.Graph, "strict digraph") {
+		t.Errorf("Test_Serve_GraphNodeLimit_1 failed: graph should be returned, but got error: %s", response.Error)
+	}
+}
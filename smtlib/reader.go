@@ -0,0 +1,418 @@
+package smtlib
+
+import (
+	"fmt"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/input"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	STRING_SORT = "String"
+	CONCAT      = "str.++"
+	EQUALS      = "="
+	EXTENSION   = ".smt2"
+)
+
+const defaultAlgorithm = "Standard"
+
+// Script is a word equation read from SMT-LIB script
+type Script struct {
+	Problem input.Problem
+	// Variables maps solver variable names to declared SMT-LIB names
+	Variables map[string]string
+	// Constants maps solver constant names to string literal characters
+	Constants map[string]string
+	CheckSat  bool
+	GetModel  bool
+}
+
+// sexpr is a symbol, a string literal or a list
+type sexpr struct {
+	list     []sexpr
+	isList   bool
+	value    string
+	isString bool
+	line     int
+}
+
+func (expr sexpr) String() string {
+	if expr.isList {
+		var parts []string
+		for _, e := range expr.list {
+			parts = append(parts, e.String())
+		}
+		return fmt.Sprintf("(%s)", strings.Join(parts, " "))
+	}
+	if expr.isString {
+		return strconv.Quote(expr.value)
+	}
+	return expr.value
+}
+
+type parser struct {
+	input []rune
+	pos   int
+	line  int
+}
+
+func (p *parser) skipSpaceAndComments() {
+	for p.pos < len(p.input) {
+		r := p.input[p.pos]
+		if r == ';' {
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+		} else if unicode.IsSpace(r) {
+			if r == '\n' {
+				p.line++
+			}
+			p.pos++
+		} else {
+			return
+		}
+	}
+}
+
+// next reads one s-expression, it returns false at the end of input
+func (p *parser) next() (sexpr, bool, error) {
+	p.skipSpaceAndComments()
+	if p.pos >= len(p.input) {
+		return sexpr{}, false, nil
+	}
+	line := p.line
+	r := p.input[p.pos]
+	switch {
+	case r == '(':
+		p.pos++
+		expr := sexpr{isList: true, line: line}
+		for {
+			p.skipSpaceAndComments()
+			if p.pos >= len(p.input) {
+				return expr, false, fmt.Errorf("line %d: unclosed '('", line)
+			}
+			if p.input[p.pos] == ')' {
+				p.pos++
+				return expr, true, nil
+			}
+			child, ok, err := p.next()
+			if err != nil {
+				return expr, false, err
+			}
+			if !ok {
+				return expr, false, fmt.Errorf("line %d: unclosed '('", line)
+			}
+			expr.list = append(expr.list, child)
+		}
+	case r == ')':
+		return sexpr{}, false, fmt.Errorf("line %d: unexpected ')'", line)
+	case r == '"':
+		value, err := p.readString()
+		return sexpr{value: value, isString: true, line: line}, err == nil, err
+	case r == '|':
+		start := p.pos + 1
+		p.pos++
+		for p.pos < len(p.input) && p.input[p.pos] != '|' {
+			if p.input[p.pos] == '\n' {
+				p.line++
+			}
+			p.pos++
+		}
+		if p.pos >= len(p.input) {
+			return sexpr{}, false, fmt.Errorf("line %d: unclosed quoted symbol", line)
+		}
+		value := string(p.input[start:p.pos])
+		p.pos++
+		return sexpr{value: value, line: line}, true, nil
+	default:
+		start := p.pos
+		for p.pos < len(p.input) && !unicode.IsSpace(p.input[p.pos]) &&
+			p.input[p.pos] != '(' && p.input[p.pos] != ')' && p.input[p.pos] != '"' && p.input[p.pos] != ';' {
+			p.pos++
+		}
+		return sexpr{value: string(p.input[start:p.pos]), line: line}, true, nil
+	}
+}
+
+// readString reads string literal, "" stands for the double quote,
+// \u{d...} and \udddd stand for unicode characters as in SMT-LIB 2.6
+func (p *parser) readString() (string, error) {
+	line := p.line
+	p.pos++
+	var raw []rune
+	for {
+		if p.pos >= len(p.input) {
+			return "", fmt.Errorf("line %d: unclosed string literal", line)
+		}
+		r := p.input[p.pos]
+		p.pos++
+		if r == '"' {
+			if p.pos < len(p.input) && p.input[p.pos] == '"' {
+				raw = append(raw, '"')
+				p.pos++
+				continue
+			}
+			break
+		}
+		if r == '\n' {
+			p.line++
+		}
+		raw = append(raw, r)
+	}
+	return unescape(raw), nil
+}
+
+func isHex(r rune) bool {
+	return unicode.Is(unicode.ASCII_Hex_Digit, r)
+}
+
+func unescape(raw []rune) string {
+	var result []rune
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '\\' && i+1 < len(raw) && raw[i+1] == 'u' {
+			if i+2 < len(raw) && raw[i+2] == '{' {
+				j := i + 3
+				for j < len(raw) && isHex(raw[j]) && j-i-3 < 5 {
+					j++
+				}
+				if j < len(raw) && raw[j] == '}' && j > i+3 {
+					code, _ := strconv.ParseInt(string(raw[i+3:j]), 16, 32)
+					result = append(result, rune(code))
+					i = j
+					continue
+				}
+			} else if i+5 < len(raw) && isHex(raw[i+2]) && isHex(raw[i+3]) && isHex(raw[i+4]) && isHex(raw[i+5]) {
+				code, _ := strconv.ParseInt(string(raw[i+2:i+6]), 16, 32)
+				result = append(result, rune(code))
+				i += 5
+				continue
+			}
+		}
+		result = append(result, raw[i])
+	}
+	return string(result)
+}
+
+// term is an element of the equation side: a declared variable or a literal character
+type term struct {
+	value      string
+	isVariable bool
+}
+
+type reader struct {
+	declared  map[string]bool
+	order     []string
+	equations [][2][]term
+	script    Script
+}
+
+func (r *reader) declare(name string, sort sexpr, line int) error {
+	if sort.isList || sort.value != STRING_SORT {
+		return fmt.Errorf("line %d: only %s constants are supported, got %s", line, STRING_SORT, sort)
+	}
+	if r.declared[name] {
+		return fmt.Errorf("line %d: %s is already declared", line, name)
+	}
+	r.declared[name] = true
+	r.order = append(r.order, name)
+	return nil
+}
+
+func (r *reader) readTerm(expr sexpr) ([]term, error) {
+	if expr.isString {
+		var terms []term
+		for _, char := range expr.value {
+			terms = append(terms, term{value: string(char)})
+		}
+		return terms, nil
+	}
+	if !expr.isList {
+		if !r.declared[expr.value] {
+			return nil, fmt.Errorf("line %d: undeclared constant: %s", expr.line, expr.value)
+		}
+		return []term{{value: expr.value, isVariable: true}}, nil
+	}
+	if len(expr.list) == 0 || expr.list[0].isList || expr.list[0].value != CONCAT {
+		return nil, fmt.Errorf("line %d: unsupported term: %s", expr.line, expr)
+	}
+	var terms []term
+	for _, arg := range expr.list[1:] {
+		argTerms, err := r.readTerm(arg)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, argTerms...)
+	}
+	return terms, nil
+}
+
+func (r *reader) readCommand(command sexpr) error {
+	if !command.isList || len(command.list) == 0 || command.list[0].isList {
+		return fmt.Errorf("line %d: invalid command: %s", command.line, command)
+	}
+	args := command.list[1:]
+	switch command.list[0].value {
+	case "set-logic", "set-info", "set-option", "exit", "push", "pop":
+		return nil
+	case "declare-fun":
+		if len(args) != 3 || args[0].isList || !args[1].isList || len(args[1].list) != 0 {
+			return fmt.Errorf("line %d: only declarations without arguments are supported: %s", command.line, command)
+		}
+		return r.declare(args[0].value, args[2], command.line)
+	case "declare-const":
+		if len(args) != 2 || args[0].isList {
+			return fmt.Errorf("line %d: invalid declaration: %s", command.line, command)
+		}
+		return r.declare(args[0].value, args[1], command.line)
+	case "assert":
+		if len(args) != 1 || !args[0].isList || len(args[0].list) != 3 || args[0].list[0].value != EQUALS {
+			return fmt.Errorf("line %d: only assertions of two strings equality are supported: %s", command.line, command)
+		}
+		left, err := r.readTerm(args[0].list[1])
+		if err != nil {
+			return err
+		}
+		right, err := r.readTerm(args[0].list[2])
+		if err != nil {
+			return err
+		}
+		r.equations = append(r.equations, [2][]term{left, right})
+		if r.script.Problem.Line == 0 {
+			r.script.Problem.Line = command.line
+		}
+		return nil
+	case "check-sat":
+		r.script.CheckSat = true
+		return nil
+	case "get-model":
+		r.script.GetModel = true
+		return nil
+	default:
+		return fmt.Errorf("line %d: unsupported command: %s", command.line, command.list[0].value)
+	}
+}
+
+// isSafeName checks that name can be used as a symbol in solver input
+func isSafeName(name string) bool {
+	if name == "" || name == "$" {
+		return false
+	}
+	for _, r := range name {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) || strings.ContainsRune("{},=", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func constantName(char string) string {
+	if isSafeName(char) {
+		return char
+	}
+	return fmt.Sprintf("U+%04X", []rune(char)[0])
+}
+
+// build names constants after literal characters and variables after declarations,
+// renaming them when they can't be used in the solver input or collide with constants
+func (r *reader) build() error {
+	if len(r.equations) == 0 {
+		return fmt.Errorf("no equation asserted")
+	}
+	if len(r.equations) > 1 {
+		return fmt.Errorf("line %d: only one equation is supported, got %d", r.script.Problem.Line, len(r.equations))
+	}
+	constNames := map[string]string{}
+	for _, side := range r.equations[0] {
+		for _, t := range side {
+			if !t.isVariable {
+				name := constantName(t.value)
+				constNames[t.value] = name
+				r.script.Constants[name] = t.value
+			}
+		}
+	}
+	varNames := map[string]string{}
+	for _, declared := range r.order {
+		name := declared
+		if !isSafeName(name) {
+			name = "v"
+			for _, char := range declared {
+				if isSafeName(string(char)) {
+					name += string(char)
+				}
+			}
+		}
+		base := name
+		for i := 1; r.script.Constants[name] != "" || r.script.Variables[name] != ""; i++ {
+			name = fmt.Sprintf("%s_%d", base, i)
+		}
+		varNames[declared] = name
+		r.script.Variables[name] = declared
+	}
+	var sides [2]string
+	for i, side := range r.equations[0] {
+		var names []string
+		for _, t := range side {
+			if t.isVariable {
+				names = append(names, varNames[t.value])
+			} else {
+				names = append(names, constNames[t.value])
+			}
+		}
+		sides[i] = strings.Join(names, " ")
+	}
+	r.script.Problem.Algorithm = defaultAlgorithm
+	r.script.Problem.Constants = formatAlphabet(r.script.Constants)
+	r.script.Problem.Variables = formatAlphabet(r.script.Variables)
+	r.script.Problem.Equation = fmt.Sprintf("%s = %s", sides[0], sides[1])
+	return nil
+}
+
+func formatAlphabet(names map[string]string) string {
+	var letters []string
+	for name := range names {
+		letters = append(letters, name)
+	}
+	sort.Strings(letters)
+	return fmt.Sprintf("{%s}", strings.Join(letters, ", "))
+}
+
+// Read reads SMT-LIB script with string declarations and one asserted equality of string concatenations,
+// characters of string literals become constants and declared strings become variables
+func Read(source io.Reader) (Script, error) {
+	bytes, err := ioutil.ReadAll(source)
+	if err != nil {
+		return Script{}, fmt.Errorf("error reading script: %v", err)
+	}
+	r := reader{
+		declared: map[string]bool{},
+		script: Script{
+			Variables: map[string]string{},
+			Constants: map[string]string{},
+			Problem:   input.Problem{Lines: map[string]int{}},
+		},
+	}
+	p := parser{input: []rune(string(bytes)), line: 1}
+	for {
+		command, ok, err := p.next()
+		if err != nil {
+			return r.script, err
+		}
+		if !ok {
+			break
+		}
+		err = r.readCommand(command)
+		if err != nil {
+			return r.script, err
+		}
+	}
+	err = r.build()
+	if err != nil {
+		return r.script, err
+	}
+	return r.script, nil
+}
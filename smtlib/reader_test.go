@@ -0,0 +1,67 @@
+package smtlib
+
+import (
+	"strings"
+	"testing"
+)
+
+var testScript = `; commutation with a literal
+(set-logic QF_S)
+(declare-fun x () String)
+(declare-const |y z| String)
+(assert (= (str.++ x "a b") (str.++ "a" (str.++ |y z| "\u{20}b"))))
+(check-sat)
+(get-model)
+`
+
+func Test_Read_1(t *testing.T) {
+	script, err := Read(strings.NewReader(testScript))
+	if err != nil {
+		t.Errorf("Test_Read_1 error should be nil: %v", err)
+		return
+	}
+	problem := script.Problem
+	if problem.Constants != "{U+0020, a, b}" || problem.Variables != "{vyz, x}" {
+		t.Errorf("Test_Read_1 failed: wrong alphabets: %s %s", problem.Constants, problem.Variables)
+	}
+	if problem.Equation != "x a U+0020 b = a vyz U+0020 b" {
+		t.Errorf("Test_Read_1 failed: wrong equation: %s", problem.Equation)
+	}
+	if script.Variables["vyz"] != "y z" || script.Constants["U+0020"] != " " || !script.CheckSat || !script.GetModel {
+		t.Errorf("Test_Read_1 failed: wrong script: %+v", script)
+	}
+	if problem.Line != 5 {
+		t.Errorf("Test_Read_1 failed: wrong line: %d", problem.Line)
+	}
+}
+
+func Test_Read_2(t *testing.T) {
+	script, err := Read(strings.NewReader(`(declare-fun a () String)(assert (= (str.++ a "a") ""))`))
+	if err != nil {
+		t.Errorf("Test_Read_2 error should be nil: %v", err)
+		return
+	}
+	if script.Problem.Equation != "a_1 a = " || script.Variables["a_1"] != "a" {
+		t.Errorf("Test_Read_2 failed: wrong equation: %s", script.Problem.Equation)
+	}
+}
+
+func Test_Read_Error_1(t *testing.T) {
+	var scripts = []struct {
+		script string
+		err    string
+	}{
+		{"(declare-fun x () Int)", "line 1: only String constants are supported, got Int"},
+		{"(declare-fun x () String)\n(assert (= x y))", "line 2: undeclared constant: y"},
+		{"(declare-fun x () String)\n(assert (= (str.len x) 1))", "line 2: unsupported term: (str.len x)"},
+		{"(declare-fun x () String)\n(assert (= x \"a\"))\n(assert (= x \"b\"))", "line 2: only one equation is supported, got 2"},
+		{"(check-sat", "line 1: unclosed '('"},
+		{"(check-sat)", "no equation asserted"},
+	}
+	for _, s := range scripts {
+		_, err := Read(strings.NewReader(s.script))
+		if err == nil || err.Error() != s.err {
+			t.Errorf("Test_Read_Error_1 failed: expected error %s, but got: %v", s.err, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package smtlib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	SAT     = "sat"
+	UNSAT   = "unsat"
+	UNKNOWN = "unknown"
+)
+
+var answers = map[string]string{
+	"TRUE":  SAT,
+	"FALSE": UNSAT,
+}
+
+// Answer returns check-sat response for the solver answer, everything but TRUE and FALSE is unknown
+func Answer(answer string) string {
+	if response, ok := answers[answer]; ok {
+		return response
+	}
+	return UNKNOWN
+}
+
+// QuoteString returns SMT-LIB string literal, non-printable and non-ASCII characters are escaped
+func QuoteString(str string) string {
+	var builder strings.Builder
+	builder.WriteRune('"')
+	for _, r := range str {
+		switch {
+		case r == '"':
+			builder.WriteString(`""`)
+		case r == '\\':
+			builder.WriteString(`\u{5c}`)
+		case r < 32 || r > 126:
+			builder.WriteString(fmt.Sprintf(`\u{%x}`, r))
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteRune('"')
+	return builder.String()
+}
+
+// QuoteSymbol returns SMT-LIB symbol, quoted if it is not a simple symbol
+func QuoteSymbol(symbol string) string {
+	simple := symbol != ""
+	for i, r := range symbol {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || strings.ContainsRune("~!@$%^&*_-+=<>.?/", r) ||
+			i > 0 && r >= '0' && r <= '9') {
+			simple = false
+		}
+	}
+	if simple {
+		return symbol
+	}
+	return fmt.Sprintf("|%s|", symbol)
+}
+
+// Model returns get-model response for the solution, names are mapped back to the script ones
+// when script is given, otherwise constants names are concatenated
+func Model(solution map[string][]string, script *Script) string {
+	var vars []string
+	for variable := range solution {
+		vars = append(vars, variable)
+	}
+	sort.Strings(vars)
+	var builder strings.Builder
+	builder.WriteString("(\n")
+	for _, variable := range vars {
+		name := variable
+		var value string
+		for _, constant := range solution[variable] {
+			if script != nil {
+				value += script.Constants[constant]
+			} else {
+				value += constant
+			}
+		}
+		if script != nil {
+			name = script.Variables[variable]
+		}
+		builder.WriteString(fmt.Sprintf("  (define-fun %s () %s %s)\n", QuoteSymbol(name), STRING_SORT, QuoteString(value)))
+	}
+	builder.WriteString(")")
+	return builder.String()
+}
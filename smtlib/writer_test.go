@@ -0,0 +1,37 @@
+package smtlib
+
+import (
+	"testing"
+)
+
+func Test_Answer_1(t *testing.T) {
+	if Answer("TRUE") != SAT || Answer("FALSE") != UNSAT || Answer("CYCLED") != UNKNOWN {
+		t.Errorf("Test_Answer_1 failed: wrong answers mapping")
+	}
+}
+
+func Test_QuoteString_1(t *testing.T) {
+	result := QuoteString("a\"b c\\α")
+	expected := `"a""b c\u{5c}\u{3b1}"`
+	if result != expected {
+		t.Errorf("Test_QuoteString_1 failed: expected %s, but got: %s", expected, result)
+	}
+}
+
+func Test_Model_1(t *testing.T) {
+	script := Script{
+		Variables: map[string]string{"vyz": "y z", "x": "x"},
+		Constants: map[string]string{"U+0020": " ", "a": "a"},
+	}
+	solution := map[string][]string{"x": {"a", "U+0020"}, "vyz": {}}
+	result := Model(solution, &script)
+	expected := "(\n  (define-fun |y z| () String \"\")\n  (define-fun x () String \"a \")\n)"
+	if result != expected {
+		t.Errorf("Test_Model_1 failed: expected %s, but got: %s", expected, result)
+	}
+	result = Model(map[string][]string{"u": {"ab", "c"}}, nil)
+	expected = "(\n  (define-fun u () String \"abc\")\n)"
+	if result != expected {
+		t.Errorf("Test_Model_1 failed: expected %s, but got: %s", expected, result)
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/google/logger"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/input"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/smtlib"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+)
+
+const megabyte = 1 << 20
+
+const (
+	AUTO   = "auto"
+	SMTLIB = "smtlib"
+)
+
+// inputFlags are flags of commands reading equations
+type inputFlags struct {
+	inputFile   *string
+	inputDir    *string
+	inputFormat *string
+}
+
+func addInputFlags(flagSet *flag.FlagSet) inputFlags {
+	return inputFlags{
+		inputFile:   flagSet.String("input_file", "", "input filename"),
+		inputDir:    flagSet.String("input_directory", "", "input directory"),
+		inputFormat: flagSet.String("input_format", AUTO, "input format: auto, text or smtlib"),
+	}
+}
+
+// files returns names of input files sorted by name, an empty name stands for stdin
+func (flags inputFlags) files() ([]string, error) {
+	if *flags.inputDir != "" {
+		inputDir, err := os.Open(*flags.inputDir)
+		if err != nil {
+			return nil, fmt.Errorf("error opening directory: %v", err)
+		}
+		files, err := inputDir.Readdir(-1)
+		inputDir.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading directory: %v", err)
+		}
+		var names []string
+		for _, file := range files {
+			if !file.IsDir() {
+				names = append(names, filepath.Join(*flags.inputDir, file.Name()))
+			}
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+	return []string{*flags.inputFile}, nil
+}
+
+// open opens input file, stdin for an empty name
+func open(fileName string) (*os.File, error) {
+	if fileName == "" {
+		return os.Stdin, nil
+	}
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening input file: %v", err)
+	}
+	return file, nil
+}
+
+// addParserFlags adds flags of equation syntax, returned function sets options after parsing
+func addParserFlags(flagSet *flag.FlagSet) func(options *solver.Options) {
+	implicitAlphabets := flagSet.Bool("implicit_alphabets", false,
+		"infer undeclared equation words: upper-case words are variables, others are constants")
+	varPrefix := flagSet.String("var_prefix", "", "with implicit alphabets words starting with prefix are variables")
+	compact := flagSet.Bool("compact", false, "equation letters are written without spaces, e.g. uxab=abux")
+	return func(options *solver.Options) {
+		options.ImplicitAlphabets = *implicitAlphabets
+		options.VarPrefix = *varPrefix
+		options.Compact = *compact
+	}
+}
+
+// addSolverFlags adds flags of solver options, returned function builds options after parsing
+func addSolverFlags(flagSet *flag.FlagSet) func() solver.Options {
+	fullGraph := flagSet.Bool("full_graph", false, "print full graph")
+	cycleRange := flagSet.Int("cycle_range", 0, "cycle depth")
+	makePng := flagSet.Bool("png", false, "create graph png")
+	outputDir := flagSet.String("output_directory", ".", "output directory")
+	gzip := flagSet.Bool("gzip", false, "write gzip-compressed graph description")
+	partSize := flagSet.Int64("part_size_mb", 0, "maximum size of one graph description file in megabytes before compression, 0 for no limit")
+	pngNodeLimit := flagSet.Int("png_node_limit", 5000, "skip png creation for graphs with more nodes, 0 for no limit")
+	generalSearch := flagSet.Bool("general_search", false,
+		"explore the tree of equations with one variable or two periodic variables instead of finding all their solutions")
+	noPreChecks := flagSet.Bool("no_precheck", false, "explore the tree without length pre-check")
+	setParserOptions := addParserFlags(flagSet)
+	return func() solver.Options {
+		options := solver.Options{
+			FullGraph:     *fullGraph,
+			MakePng:       *makePng,
+			CycleRange:    *cycleRange,
+			OutputDir:     *outputDir,
+			OutputFiles:   solver.NewOutputFiles(),
+			Gzip:          *gzip,
+			PartSize:      *partSize * megabyte,
+			PngNodeLimit:  *pngNodeLimit,
+			GeneralSearch: *generalSearch,
+			NoPreChecks:   *noPreChecks,
+		}
+		setParserOptions(&options)
+		return options
+	}
+}
+
+// parseFlags parses command flags, it returns exit code and false if the command shouldn't run
+func parseFlags(flagSet *flag.FlagSet, args []string) (int, bool) {
+	err := flagSet.Parse(args)
+	if err == flag.ErrHelp {
+		return 0, false
+	}
+	if err != nil {
+		return 2, false
+	}
+	return 0, true
+}
+
+func runSolve(args []string) int {
+	cmd, _ := findCommand(SOLVE)
+	flagSet := newFlagSet(cmd)
+	inputs := addInputFlags(flagSet)
+	getOptions := addSolverFlags(flagSet)
+	output := flagSet.String("output", TEXT, "output format: text, json, ndjson or smtlib")
+	model := flagSet.Bool("model", false, "print model in smtlib output as if (get-model) was given")
+	stats := flagSet.Bool("stats", false, "print search statistics")
+	tree := flagSet.Bool("tree_json", false, "write JSON description of the search tree next to the graph description")
+	jobs := flagSet.Int("jobs", 1, "number of input files solved at once")
+	timeout := flagSet.Duration("timeout", 0, "maximum time of solving one input file, "+
+		"searches still running are stopped with TIMEOUT answer, 0 for no limit")
+	if code, ok := parseFlags(flagSet, args); !ok {
+		return code
+	}
+	if *jobs < 1 {
+		fmt.Fprintln(flagSet.Output(), "jobs should be positive")
+		return 2
+	}
+	options := getOptions()
+	printer, err := newResultPrinter(*output, *stats, *model)
+	if err != nil {
+		logger.Errorf("error parsing flags: %v", err)
+		return 2
+	}
+	defer printer.Close()
+	files, err := inputs.files()
+	if err != nil {
+		logger.Errorf("error reading input: %v", err)
+		return 1
+	}
+	start := time.Now()
+	var summary solveSummary
+	solveFiles(files, *jobs, func(fileName string) []processResult {
+		ctx := context.Background()
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+		inputFile, err := open(fileName)
+		if err != nil {
+			logger.Errorf("%v", err)
+			return []processResult{{FileName: fileName, Error: err.Error()}}
+		}
+		defer inputFile.Close()
+		return process(ctx, inputFile, fileName, *inputs.inputFormat, options, *tree, printer.statistics)
+	}, func(results []processResult) {
+		for _, result := range results {
+			printer.Print(result)
+			summary.add(result)
+		}
+	})
+	if *inputs.inputDir != "" {
+		summary.print(os.Stderr, len(files), time.Since(start))
+	}
+	if summary.errors > 0 {
+		return 1
+	}
+	return 0
+}
+
+// solveFiles solves files with solveFile, at most jobs files at once,
+// results are passed to handle in the order of files
+func solveFiles(files []string, jobs int, solveFile func(fileName string) []processResult,
+	handle func(results []processResult)) {
+	results := make([]chan []processResult, len(files))
+	for i := range files {
+		results[i] = make(chan []processResult, 1)
+	}
+	go func() {
+		slots := make(chan struct{}, jobs)
+		for i, fileName := range files {
+			slots <- struct{}{}
+			go func(i int, fileName string) {
+				results[i] <- solveFile(fileName)
+				<-slots
+			}(i, fileName)
+		}
+	}()
+	for i := range files {
+		handle(<-results[i])
+	}
+}
+
+// solveSummary counts answers of solved problems
+type solveSummary struct {
+	answers map[string]int
+	errors  int
+}
+
+func (summary *solveSummary) add(result processResult) {
+	if result.Error != "" {
+		summary.errors++
+		return
+	}
+	if summary.answers == nil {
+		summary.answers = map[string]int{}
+	}
+	summary.answers[result.Answer]++
+}
+
+func (summary *solveSummary) print(output io.Writer, files int, duration time.Duration) {
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "files\tTRUE\tFALSE\tCYCLED\tTIMEOUT\tERROR\ttotal time\t")
+	fmt.Fprintf(writer, "%d\t%d\t%d\t%d\t%d\t%d\t%s\t\n", files, summary.answers[solver.TRUE],
+		summary.answers[solver.FALSE], summary.answers[solver.CYCLED], summary.answers[solver.TIMEOUT],
+		summary.errors, duration)
+	writer.Flush()
+}
+
+// process solves problems read from input source, ctx limits the time of solving them all
+func process(ctx context.Context, inputSource io.Reader, fileName string, inputFormat string,
+	options solver.Options, tree bool, withStatistics bool) []processResult {
+	problems, script, err := readProblems(inputSource, fileName, inputFormat)
+	var results []processResult
+	if err != nil {
+		logger.Errorf("error reading input: %v", err)
+		results = append(results, processResult{FileName: fileName, Error: fmt.Sprintf("error reading input: %v", err)})
+	}
+	for _, problem := range problems {
+		result := solveProblem(ctx, problem, fileName, options, withStatistics, tree)
+		result.script = script
+		results = append(results, result)
+	}
+	return results
+}
+
+// readProblems reads problems in the input format, automatic format is SMT-LIB for files with .smt2 extension
+// or input starting with a parenthesis and text otherwise, SMT-LIB script is returned to print models
+func readProblems(inputSource io.Reader, fileName string, inputFormat string) ([]input.Problem, *smtlib.Script, error) {
+	reader := bufio.NewReader(inputSource)
+	if inputFormat == AUTO {
+		inputFormat = TEXT
+		if strings.HasSuffix(fileName, smtlib.EXTENSION) {
+			inputFormat = SMTLIB
+		} else {
+			for {
+				r, _, err := reader.ReadRune()
+				if err != nil {
+					break
+				}
+				if !unicode.IsSpace(r) {
+					if r == '(' || r == ';' {
+						inputFormat = SMTLIB
+					}
+					reader.UnreadRune()
+					break
+				}
+			}
+		}
+	}
+	switch inputFormat {
+	case TEXT:
+		problems, err := input.Read(reader)
+		return problems, nil, err
+	case SMTLIB:
+		script, err := smtlib.Read(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading SMT-LIB script: %v", err)
+		}
+		return []input.Problem{script.Problem}, &script, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid input format: %s", inputFormat)
+	}
+}
+
+// problemOptions returns options with per-problem settings applied
+func problemOptions(problem input.Problem, options solver.Options) solver.Options {
+	if problem.CycleRange != 0 {
+		options.CycleRange = problem.CycleRange
+	}
+	if problem.Implicit {
+		options.ImplicitAlphabets = true
+	}
+	return options
+}
+
+func solveProblem(ctx context.Context, problem input.Problem, fileName string, options solver.Options, withStatistics bool,
+	tree bool) processResult {
+	result := processResult{
+		FileName:  fileName,
+		Name:      problem.Name,
+		Line:      problem.Line,
+		Equation:  problem.Equation,
+		Algorithm: problem.Algorithm,
+		Expected:  problem.Expect,
+	}
+	if problem.Err != nil {
+		logger.Errorf("error reading problem: %v", problem.Err)
+		result.Error = fmt.Sprintf("error reading problem: %v", problem.Err)
+		return result
+	}
+	var solver solver.Solver
+	err := solver.InitWithOptions(problem.Algorithm, problem.Constants, problem.Variables, problem.Equation,
+		problemOptions(problem, options))
+	if err != nil {
+		logger.Errorf("error initializing solver: line %d: %v", problem.Line, err)
+		result.Error = fmt.Sprintf("error initializing solver: line %d: %v", problem.Line, err)
+		return result
+	}
+	_, _, err = solver.SolveContext(ctx)
+	result = newProcessResult(result, solver.GetResult(), withStatistics)
+	if err != nil {
+		logger.Errorf("error writing graph: %v", err)
+		result.Error = fmt.Sprintf("error writing graph: %v", err)
+		return result
+	}
+	if tree {
+		err = solver.WriteTree()
+		if err != nil {
+			logger.Errorf("error writing tree: %v", err)
+			result.Error = fmt.Sprintf("error writing tree: %v", err)
+		}
+	}
+	return result
+}
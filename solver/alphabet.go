@@ -1,23 +1,29 @@
 package solver
 
-import "fmt"
+import (
+	"fmt"
+	"unicode/utf8"
+)
 
 const (
 	OPENBR  = "{"
 	CLOSEBR = "}"
 	COMMA   = ","
-	SPACE   = " "
 )
 
 type Alphabet struct {
-	words         []string
-	size          int
+	words []string
+	size  int
+	// maxWordLength is the length of the longest word in runes
 	maxWordLength int
 }
 
 func (alphabet *Alphabet) AddWord(word string) {
 	alphabet.words = append(alphabet.words, word)
 	alphabet.size++
+	if length := utf8.RuneCountInString(word); length > alphabet.maxWordLength {
+		alphabet.maxWordLength = length
+	}
 }
 
 func (alphabet *Alphabet) Has(word string) bool {
@@ -0,0 +1,227 @@
+package solver
+
+import (
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/input"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"os"
+	"testing"
+	"time"
+)
+
+const (
+	corpusFILENAME   = "testdata/classic.txt"
+	corpusCycleRange = 30
+)
+
+var corpusAlgorithmTypes = []string{"Standard", "Finite"}
+
+func readCorpus(tb testing.TB) []input.Problem {
+	file, err := os.Open(corpusFILENAME)
+	if err != nil {
+		tb.Fatalf("error opening corpus: %v", err)
+	}
+	defer file.Close()
+	problems, err := input.Read(file)
+	if err != nil {
+		tb.Fatalf("error reading corpus: %v", err)
+	}
+	for _, problem := range problems {
+		if problem.Err != nil {
+			tb.Fatalf("error reading corpus problem %s: %v", problem.Name, problem.Err)
+		}
+	}
+	return problems
+}
+
+// solveCorpusProblem explores the full tree of problem without writing graph, the tree is explored
+// even for equations dedicated procedures or pre-checks answer without it, so nodes counts stay comparable
+func solveCorpusProblem(algorithmType string, problem input.Problem) (*Solver, error) {
+	return solveCorpusProblemWithOptions(algorithmType, problem, Options{FullGraph: true, CycleRange: corpusCycleRange,
+		DiscardGraph: true, GeneralSearch: true, NoPreChecks: true})
+}
+
+func solveCorpusProblemWithOptions(algorithmType string, problem input.Problem, options Options) (*Solver, error) {
+	var solver Solver
+	err := solver.InitWithOptions(algorithmType, problem.Constants, problem.Variables, problem.Equation, options)
+	if err != nil {
+		return nil, err
+	}
+	_, _, err = solver.Solve()
+	return &solver, err
+}
+
+func Test_Corpus_1(t *testing.T) {
+	for _, algorithmType := range corpusAlgorithmTypes {
+		for _, problem := range readCorpus(t) {
+			solver, err := solveCorpusProblem(algorithmType, problem)
+			if err != nil {
+				t.Errorf("Test_Corpus_1 error should be nil: %s: %v", problem.Name, err)
+				continue
+			}
+			if solver.GetResult().Answer != problem.Expect {
+				t.Errorf("Test_Corpus_1 failed: %s %s: expected: %s, but got: %s", algorithmType, problem.Name,
+					problem.Expect, solver.GetResult().Answer)
+			}
+		}
+	}
+}
+
+// Test_Corpus_OneVariable_1 checks corpus equations with one variable are answered without exploring the tree
+func Test_Corpus_OneVariable_1(t *testing.T) {
+	for _, algorithmType := range corpusAlgorithmTypes {
+		for _, problem := range readCorpus(t) {
+			variables, err := parseAlphabet(problem.Variables)
+			if err != nil {
+				t.Fatalf("error parsing variables of %s: %v", problem.Name, err)
+			}
+			if variables.size == 1 {
+				solver, err := solveCorpusProblemWithOptions(algorithmType, problem, Options{DiscardGraph: true})
+				if err != nil {
+					t.Errorf("Test_Corpus_OneVariable_1 error should be nil: %s: %v", problem.Name, err)
+					continue
+				}
+				result := solver.GetResult()
+				if result.OneVariable == nil || result.NodesCount != 1 {
+					t.Errorf("Test_Corpus_OneVariable_1 failed: %s %s: equation should be answered by one variable solver",
+						algorithmType, problem.Name)
+				}
+				if result.Answer != problem.Expect {
+					t.Errorf("Test_Corpus_OneVariable_1 failed: %s %s: expected: %s, but got: %s", algorithmType,
+						problem.Name, problem.Expect, result.Answer)
+				}
+			}
+		}
+	}
+}
+
+// Test_Corpus_PreCheck_1 checks default pre-checks answer FALSE corpus equations only
+func Test_Corpus_PreCheck_1(t *testing.T) {
+	expectedPreChecks := map[string]string{
+		"no_conjugate":  "length: numbers of a on the sides can't be equal",
+		"letters_count": "length: numbers of a on the sides can't be equal",
+	}
+	for _, algorithmType := range corpusAlgorithmTypes {
+		for _, problem := range readCorpus(t) {
+			solver, err := solveCorpusProblemWithOptions(algorithmType, problem,
+				Options{CycleRange: corpusCycleRange, DiscardGraph: true, GeneralSearch: true})
+			if err != nil {
+				t.Errorf("Test_Corpus_PreCheck_1 error should be nil: %s: %v", problem.Name, err)
+				continue
+			}
+			result := solver.GetResult()
+			if result.PreCheck != expectedPreChecks[problem.Name] {
+				t.Errorf("Test_Corpus_PreCheck_1 failed: %s %s: expected pre-check: %q, but got: %q", algorithmType,
+					problem.Name, expectedPreChecks[problem.Name], result.PreCheck)
+			}
+			if result.Answer != problem.Expect {
+				t.Errorf("Test_Corpus_PreCheck_1 failed: %s %s: expected: %s, but got: %s", algorithmType,
+					problem.Name, problem.Expect, result.Answer)
+			}
+		}
+	}
+}
+
+// BenchmarkSolve explores full trees of corpus equations, nodes/s is the number of explored nodes per second
+func BenchmarkSolve(b *testing.B) {
+	problems := readCorpus(b)
+	for _, algorithmType := range corpusAlgorithmTypes {
+		for _, problem := range problems {
+			b.Run(algorithmType+"/"+problem.Name, func(b *testing.B) {
+				b.ReportAllocs()
+				nodes := 0
+				start := time.Now()
+				for i := 0; i < b.N; i++ {
+					solver, err := solveCorpusProblem(algorithmType, problem)
+					if err != nil {
+						b.Fatalf("error solving %s: %v", problem.Name, err)
+					}
+					nodes += solver.GetResult().NodesCount
+				}
+				b.ReportMetric(float64(nodes)/time.Since(start).Seconds(), "nodes/s")
+			})
+		}
+	}
+}
+
+// BenchmarkSolveTwoVariables answers corpus equations with two periodic variables without exploring the tree
+func BenchmarkSolveTwoVariables(b *testing.B) {
+	problems := readCorpus(b)
+	for _, algorithmType := range corpusAlgorithmTypes {
+		for _, problem := range problems {
+			solver, err := solveCorpusProblemWithOptions(algorithmType, problem, Options{DiscardGraph: true})
+			if err != nil {
+				b.Fatalf("error solving %s: %v", problem.Name, err)
+			}
+			if solver.GetResult().TwoVariables == nil {
+				continue
+			}
+			b.Run(algorithmType+"/"+problem.Name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_, err := solveCorpusProblemWithOptions(algorithmType, problem, Options{DiscardGraph: true})
+					if err != nil {
+						b.Fatalf("error solving %s: %v", problem.Name, err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkLengthCheck(b *testing.B) {
+	equation := benchmarkEquation(b)
+	var check LengthCheck
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		check.Check(equation.leftPart, equation.rightPart)
+	}
+}
+
+// benchmarkEquation is long enough for equation operations to dominate the loop
+func benchmarkEquation(b *testing.B) Equation {
+	constants, err := parseAlphabet("{a, b}")
+	if err != nil {
+		b.Fatalf("error parsing constants: %v", err)
+	}
+	vars, err := parseAlphabet("{x, y, z}")
+	if err != nil {
+		b.Fatalf("error parsing variables: %v", err)
+	}
+	var equation Equation
+	err = equation.Init("x a y b z x a b y y a z = z b x a y a b z x b y x", &constants, &vars)
+	if err != nil {
+		b.Fatalf("error parsing equation: %v", err)
+	}
+	return equation
+}
+
+func BenchmarkSubstitute(b *testing.B) {
+	equation := benchmarkEquation(b)
+	variable := symbol.Symbol(symbol.Var("x"))
+	newSymbols := []symbol.Symbol{symbol.Var("y"), symbol.Var("x")}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		equation.Substitute(&variable, newSymbols)
+	}
+}
+
+func BenchmarkReduce(b *testing.B) {
+	equation := benchmarkEquation(b)
+	equation.leftPart = append([]symbol.Symbol{symbol.Const("a"), symbol.Var("y")}, equation.leftPart...)
+	equation.rightPart = append([]symbol.Symbol{symbol.Const("a"), symbol.Var("y")}, equation.rightPart...)
+	equation.leftLength, equation.rightLength = len(equation.leftPart), len(equation.rightPart)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reduced := equation
+		reduced.Reduce()
+	}
+}
+
+func BenchmarkCheckSameness(b *testing.B) {
+	equation := benchmarkEquation(b)
+	same := benchmarkEquation(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		equation.CheckSameness(&same)
+	}
+}
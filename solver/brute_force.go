@@ -0,0 +1,75 @@
+package solver
+
+import (
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+)
+
+// words returns all words over constants of length at most maxLength, shorter words go first
+func words(constants []string, maxLength int) [][]string {
+	result := [][]string{{}}
+	previous := [][]string{{}}
+	for length := 1; length <= maxLength && len(constants) > 0; length++ {
+		var current [][]string
+		for _, word := range previous {
+			for _, constant := range constants {
+				current = append(current, append(append([]string{}, word...), constant))
+			}
+		}
+		result = append(result, current...)
+		previous = current
+	}
+	return result
+}
+
+// equationVariables returns variables of equation in the order of their first occurrence
+func equationVariables(equation *Equation) []string {
+	var variables []string
+	seen := map[string]bool{}
+	for _, part := range [][]symbol.Symbol{equation.leftPart, equation.rightPart} {
+		for _, sym := range part {
+			if symbol.IsVar(sym) && !seen[sym.Value()] {
+				seen[sym.Value()] = true
+				variables = append(variables, sym.Value())
+			}
+		}
+	}
+	return variables
+}
+
+// BruteForce checks every assignment of variables to words of at most maxLength constants
+// and returns the first solution found, it doesn't depend on the search tree, so it's used
+// to check Solve answers on small instances; variables not occurring in equation are empty
+func (solver *Solver) BruteForce(maxLength int) (map[string][]string, bool) {
+	variables := equationVariables(&solver.equation)
+	values := words(solver.constantsAlph.words, maxLength)
+	assignment := map[string][]symbol.Symbol{}
+	var search func(index int) bool
+	search = func(index int) bool {
+		if index == len(variables) {
+			return substituteAssignment(solver.equation.leftPart, assignment) ==
+				substituteAssignment(solver.equation.rightPart, assignment)
+		}
+		for _, value := range values {
+			symbols := make([]symbol.Symbol, len(value))
+			for i, constant := range value {
+				symbols[i] = symbol.Const(constant)
+			}
+			assignment[variables[index]] = symbols
+			if search(index + 1) {
+				return true
+			}
+		}
+		return false
+	}
+	if !search(0) {
+		return nil, false
+	}
+	solution := map[string][]string{}
+	for _, word := range solver.varsAlph.words {
+		solution[word] = []string{}
+		for _, sym := range assignment[word] {
+			solution[word] = append(solution[word], sym.Value())
+		}
+	}
+	return solution, true
+}
@@ -0,0 +1,88 @@
+package solver
+
+import (
+	"context"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/generator"
+	"testing"
+	"time"
+)
+
+func Test_BruteForce_1(t *testing.T) {
+	tests := []struct {
+		equation string
+		found    bool
+	}{
+		{"x y = y x", true},
+		{"x a = b x", false},
+		{"b = b y", true},
+		{"x a b = a b x", true},
+		{"x x = a b", false},
+	}
+	for _, test := range tests {
+		var solver Solver
+		err := solver.InitWithOptions("Standard", "{a, b}", "{x, y}", test.equation, Options{DiscardGraph: true})
+		if err != nil {
+			t.Errorf("Test_BruteForce_1 error should be nil: %v", err)
+			continue
+		}
+		solution, found := solver.BruteForce(3)
+		if found != test.found {
+			t.Errorf("Test_BruteForce_1 failed: %s: expected found: %v, but got: %v", test.equation, test.found, found)
+			continue
+		}
+		if found && !checkSolution(test.equation, solution) {
+			t.Errorf("Test_BruteForce_1 failed: %s: wrong solution: %v", test.equation, solution)
+		}
+	}
+}
+
+// Test_Differential_1 compares Solve answers on generated equations with brute force:
+// an equation which has a short solution can't be answered FALSE, quadratic equations can't be answered CYCLED,
+// solutions of TRUE answers must be valid, searches running too long are skipped
+func Test_Differential_1(t *testing.T) {
+	tests := []generator.Options{
+		{Shape: generator.QUADRATIC, MaxLength: 5},
+		{Shape: generator.QUADRATIC, MaxLength: 5, Satisfiable: true},
+		{Shape: generator.ANY, MaxLength: 4},
+		{Shape: generator.ANY, MaxLength: 4, VariablesCount: 1, Satisfiable: true},
+	}
+	for i, options := range tests {
+		options.Constants = []string{"a", "b"}
+		options.Variables = []string{"x", "y"}
+		options.Seed = int64(i + 1)
+		gen, err := generator.New(options)
+		if err != nil {
+			t.Errorf("Test_Differential_1 error should be nil: %v", err)
+			continue
+		}
+		for j := 0; j < 100; j++ {
+			equation, err := gen.Next()
+			if err != nil {
+				t.Errorf("Test_Differential_1 error should be nil: %v", err)
+				continue
+			}
+			var solver Solver
+			err = solver.InitWithOptions("Standard", equation.ConstantsString(), equation.VariablesString(),
+				equation.String(), Options{DiscardGraph: true, CycleRange: 20})
+			if err != nil {
+				t.Errorf("Test_Differential_1 error should be nil: %s: %v", equation, err)
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+			answer, _, err := solver.SolveContext(ctx)
+			cancel()
+			if err != nil {
+				t.Errorf("Test_Differential_1 error should be nil: %s: %v", equation, err)
+				continue
+			}
+			solution, found := solver.BruteForce(3)
+			if found && answer == FALSE {
+				t.Errorf("Test_Differential_1 failed: %s has solution %v, but got answer: %s", equation,
+					solution, answer)
+			}
+			if answer == TRUE && !checkSolution(equation.String(), solver.GetResult().Solution) {
+				t.Errorf("Test_Differential_1 failed: %s: wrong solution: %v", equation, solver.GetResult().Solution)
+			}
+		}
+	}
+}
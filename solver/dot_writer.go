@@ -3,24 +3,163 @@ package solver
 import (
 	"fmt"
 	"github.com/goccy/go-graphviz"
+	"github.com/google/logger"
 	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
-	"io/ioutil"
+	"io"
+	"os"
+	"strings"
+	"sync"
 )
 
+const (
+	solutionColor = `"#228b22"`
+	trueColor     = `"#98fb98"`
+	falseColor    = `"#ffb6c1"`
+	cycledColor   = `"#ffa07a"`
+	cutColor      = `"#d3d3d3"`
+)
+
+const (
+	PNG = "png"
+	SVG = "svg"
+)
+
+const (
+	DOTHeader = "strict digraph word_eq {\n"
+	DOTFooter = "}"
+)
+
+var rulesColors = map[int]string{
+	FIRST_RULE:               `"#0000ff"`,
+	FIRST_RULE_FINITE:        `"#000080"`,
+	SECOND_RULE_LEFT:         `"#ff8c00"`,
+	SECOND_RULE_RIGHT:        `"#800080"`,
+	SECOND_RULE_LEFT_FINITE:  `"#d2691e"`,
+	SECOND_RULE_RIGHT_FINITE: `"#ff00ff"`,
+	THIRD_RULE:               `"#666666"`,
+	FOURTH_RULE_LEFT:         `"#008b8b"`,
+	FOURTH_RULE_RIGHT:        `"#a52a2a"`,
+}
+
 type DotWriter struct {
-	writer Writer
+	writer       Writer
+	nodesCount   int
+	pngNodeLimit int
+	// memoryNodeLimit is the maximum number of nodes of graph kept in memory, 0 means no limit
+	memoryNodeLimit int
+}
+
+// escapeDOT escapes string to be used inside of a double-quoted DOT string
+func escapeDOT(str string) string {
+	var builder strings.Builder
+	for _, r := range str {
+		switch r {
+		case '"', '\\':
+			builder.WriteRune('\\')
+			builder.WriteRune(r)
+		case '\n':
+			builder.WriteString("\\n")
+		case '\r':
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// dotID returns quoted DOT identifier
+func dotID(id string) string {
+	return fmt.Sprintf("\"%s\"", escapeDOT(id))
+}
+
+func nodeStatement(number string, label string) string {
+	return fmt.Sprintf("    %s [label=\"%s\"];\n", dotID(number), escapeDOT(label))
+}
+
+func edgeStatement(from string, to string, rule int, label string) string {
+	return fmt.Sprintf("     %s -> %s[label=\"%s\", color=%s, fontcolor=%s];\n",
+		dotID(from), dotID(to), escapeDOT(label), rulesColors[rule], rulesColors[rule])
+}
+
+func infoNodeStatement(number string, value string) string {
+	color := falseColor
+	if value == TRUE {
+		color = trueColor
+	}
+	return fmt.Sprintf("    %s [label=\"%s\", shape=box, style=filled, fillcolor=%s];\n",
+		dotID(number), escapeDOT(value), color)
+}
+
+func infoEdgeStatement(from string, to string) string {
+	return fmt.Sprintf("     %s -> %s;\n", dotID(from), dotID(to))
+}
+
+func dottedEdgeStatement(from string, to string) string {
+	return fmt.Sprintf("     %s -> %s [style=dotted];\n", dotID(from), dotID(to))
+}
+
+func cycledNodeStatement(number string) string {
+	return fmt.Sprintf("    %s [style=filled, fillcolor=%s];\n", dotID(number), cycledColor)
+}
+
+func cutNodeStatement(number string) string {
+	return fmt.Sprintf("    %s [style=\"filled,dashed\", fillcolor=%s];\n", dotID(number), cutColor)
+}
+
+func solutionNodeStatement(number string) string {
+	return fmt.Sprintf("    %s [color=%s, penwidth=2];\n", dotID(number), solutionColor)
+}
+
+func solutionEdgeStatement(from string, to string) string {
+	return fmt.Sprintf("     %s -> %s [color=%s, penwidth=2];\n", dotID(from), dotID(to), solutionColor)
 }
 
-func (dotWriter *DotWriter) Init(mode string, eq string, outputDir string) error {
-	err := dotWriter.writer.Init(mode, eq, outputDir)
+// legendStatement describes leaves styles and rules colors in a separate cluster
+func legendStatement() string {
+	legend := "    subgraph cluster_legend {\n" +
+		"        label=\"legend\";\n" +
+		"        node [shape=box, style=filled];\n" +
+		fmt.Sprintf("        legend_true [label=\"TRUE\", fillcolor=%s];\n", trueColor) +
+		fmt.Sprintf("        legend_false [label=\"FALSE\", fillcolor=%s];\n", falseColor) +
+		fmt.Sprintf("        legend_cycled [label=\"cycle\", shape=ellipse, fillcolor=%s];\n", cycledColor) +
+		fmt.Sprintf("        legend_cut [label=\"depth cutoff\", shape=ellipse, style=\"filled,dashed\", fillcolor=%s];\n", cutColor) +
+		fmt.Sprintf("        legend_solution [label=\"solution path\", style=solid, color=%s, penwidth=2];\n", solutionColor)
+	for i, rule := range rules {
+		legend += fmt.Sprintf("        legend_rule_%d [label=\"[%s] %s rule\", style=solid, color=%s, fontcolor=%s];\n",
+			i, RuleTag(rule), RuleName(rule), rulesColors[rule], rulesColors[rule])
+	}
+	return legend + "    }\n"
+}
+
+func (dotWriter *DotWriter) Init(mode string, eq string, outputDir string, files *OutputFiles, compress bool,
+	partSize int64, pngNodeLimit int) error {
+	dotWriter.pngNodeLimit = pngNodeLimit
+	err := dotWriter.writer.Init(mode, eq, outputDir, files, compress, partSize)
 	if err != nil {
 		return fmt.Errorf("error initing writer: %v", err)
 	}
 	return nil
 }
 
+// InitMemory makes writer keep graph description in memory, it's dropped once more than nodeLimit nodes
+// are written, unless nodeLimit is 0
+func (dotWriter *DotWriter) InitMemory(nodeLimit int) {
+	dotWriter.memoryNodeLimit = nodeLimit
+	dotWriter.writer.InitMemory()
+}
+
+// IsDropped checks that graph description kept in memory was dropped as it exceeded the node limit
+func (dotWriter *DotWriter) IsDropped() bool {
+	return dotWriter.writer.dropped
+}
+
+// InitDiscard makes writer drop graph description
+func (dotWriter *DotWriter) InitDiscard() {
+	dotWriter.writer.InitDiscard()
+}
+
 func (dotWriter *DotWriter) StartDOTDescription() error {
-	err := dotWriter.writer.Write("digraph word_eq {\n")
+	err := dotWriter.writer.Write(DOTHeader)
 	if err != nil {
 		return fmt.Errorf("error starting DOT description: %v", err)
 	}
@@ -28,7 +167,11 @@ func (dotWriter *DotWriter) StartDOTDescription() error {
 }
 
 func (dotWriter *DotWriter) EndDOTDescription(makePng bool) error {
-	err := dotWriter.writer.Write("}")
+	err := dotWriter.WriteLegend()
+	if err != nil {
+		return fmt.Errorf("error ending DOT description: %v", err)
+	}
+	err = dotWriter.writer.Write(DOTFooter)
 	if err != nil {
 		return fmt.Errorf("error ending DOT description: %v", err)
 	}
@@ -36,6 +179,15 @@ func (dotWriter *DotWriter) EndDOTDescription(makePng bool) error {
 	if err != nil {
 		return fmt.Errorf("error flushing DOT description: %v", err)
 	}
+	err = dotWriter.writer.Close()
+	if err != nil {
+		return fmt.Errorf("error closing DOT description: %v", err)
+	}
+	if makePng && dotWriter.pngNodeLimit > 0 && dotWriter.nodesCount > dotWriter.pngNodeLimit {
+		logger.Warningf("skipping png creation for %s: graph has %d nodes, limit is %d",
+			dotWriter.writer.GetPicFilename(), dotWriter.nodesCount, dotWriter.pngNodeLimit)
+		return nil
+	}
 	if makePng {
 		err = dotWriter.CreatePNG()
 		if err != nil {
@@ -45,16 +197,21 @@ func (dotWriter *DotWriter) EndDOTDescription(makePng bool) error {
 	return nil
 }
 
-func getEdgeLabel(symbol *symbol.Symbol, newSymbols []symbol.Symbol) string {
-	label := fmt.Sprintf("%s->", (*symbol).Value())
+func getEdgeLabel(rule int, symbol *symbol.Symbol, newSymbols []symbol.Symbol) string {
+	return fmt.Sprintf("[%s] %s", RuleTag(rule), substitutionString(symbol, newSymbols))
+}
+
+// substitutionString describes substitution as x->yx
+func substitutionString(symbol *symbol.Symbol, newSymbols []symbol.Symbol) string {
+	str := fmt.Sprintf("%s->", (*symbol).Value())
 	for _, sym := range newSymbols {
-		label += sym.Value()
+		str += sym.Value()
 	}
-	return label
+	return str
 }
 
 func (dotWriter *DotWriter) WriteEdge(from *Node, to *Node) error {
-	err := dotWriter.writer.Write(fmt.Sprintf("     %s -> %s;\n", from.Number, to.Number))
+	err := dotWriter.writer.Write(edgeStatement(from.Number, to.Number, to.Rule, fmt.Sprintf("[%s]", RuleTag(to.Rule))))
 	if err != nil {
 		return fmt.Errorf("error describing edge: %v", err)
 	}
@@ -62,7 +219,7 @@ func (dotWriter *DotWriter) WriteEdge(from *Node, to *Node) error {
 }
 
 func (dotWriter *DotWriter) WriteLabelEdge(from *Node, to *Node, symbol *symbol.Symbol, newSymbols []symbol.Symbol) error {
-	err := dotWriter.writer.Write(fmt.Sprintf("     %s -> %s[label=\"%s\"];\n", from.Number, to.Number, getEdgeLabel(symbol, newSymbols)))
+	err := dotWriter.writer.Write(edgeStatement(from.Number, to.Number, to.Rule, getEdgeLabel(to.Rule, symbol, newSymbols)))
 	if err != nil {
 		return fmt.Errorf("error describing edge: %v", err)
 	}
@@ -70,7 +227,7 @@ func (dotWriter *DotWriter) WriteLabelEdge(from *Node, to *Node, symbol *symbol.
 }
 
 func (dotWriter *DotWriter) WriteInfoEdge(from *Node, to InfoNode) error {
-	err := dotWriter.writer.Write(fmt.Sprintf("     %s -> %s;\n", from.Number, to.GetNumber()))
+	err := dotWriter.writer.Write(infoEdgeStatement(from.Number, to.GetNumber()))
 	if err != nil {
 		return fmt.Errorf("error describing edge: %v", err)
 	}
@@ -78,7 +235,7 @@ func (dotWriter *DotWriter) WriteInfoEdge(from *Node, to InfoNode) error {
 }
 
 func (dotWriter *DotWriter) WriteDottedEdge(from *Node, to *Node) error {
-	err := dotWriter.writer.Write(fmt.Sprintf("     %s -> %s [style=dotted];\n", from.Number, to.Number))
+	err := dotWriter.writer.Write(dottedEdgeStatement(from.Number, to.Number))
 	if err != nil {
 		return fmt.Errorf("error describing edge: %v", err)
 	}
@@ -86,7 +243,11 @@ func (dotWriter *DotWriter) WriteDottedEdge(from *Node, to *Node) error {
 }
 
 func (dotWriter *DotWriter) WriteNode(node *Node) error {
-	err := dotWriter.writer.Write(fmt.Sprintf("    %s [label=\"%s\"];\n", node.Number, node.Value.String()))
+	dotWriter.nodesCount++
+	if dotWriter.memoryNodeLimit > 0 && dotWriter.nodesCount > dotWriter.memoryNodeLimit && !dotWriter.IsDropped() {
+		dotWriter.writer.Drop()
+	}
+	err := dotWriter.writer.Write(nodeStatement(node.Number, node.Value.String()))
 	if err != nil {
 		return fmt.Errorf("error describing node: %v", err)
 	}
@@ -94,24 +255,111 @@ func (dotWriter *DotWriter) WriteNode(node *Node) error {
 }
 
 func (dotWriter *DotWriter) WriteInfoNode(node InfoNode) error {
-	err := dotWriter.writer.Write(fmt.Sprintf("    %s [label=\"%s\"];\n", node.GetNumber(), node.GetValue()))
+	err := dotWriter.writer.Write(infoNodeStatement(node.GetNumber(), node.GetValue()))
+	if err != nil {
+		return fmt.Errorf("error describing node: %v", err)
+	}
+	return nil
+}
+
+// WriteCycledNode restyles node that repeats one of its ancestors
+func (dotWriter *DotWriter) WriteCycledNode(node *Node) error {
+	err := dotWriter.writer.Write(cycledNodeStatement(node.Number))
 	if err != nil {
 		return fmt.Errorf("error describing node: %v", err)
 	}
 	return nil
 }
 
+// WriteCutNode restyles node that exceeded cycle range and was not explored
+func (dotWriter *DotWriter) WriteCutNode(node *Node) error {
+	err := dotWriter.writer.Write(cutNodeStatement(node.Number))
+	if err != nil {
+		return fmt.Errorf("error describing node: %v", err)
+	}
+	return nil
+}
+
+// WriteSolutionPath highlights all nodes and edges from tree root to the TRUE node,
+// attributes are merged with already written ones as graph is strict
+func (dotWriter *DotWriter) WriteSolutionPath(node *Node, trueNode InfoNode) error {
+	err := dotWriter.writer.Write(solutionEdgeStatement(node.Number, trueNode.GetNumber()))
+	if err != nil {
+		return fmt.Errorf("error describing solution path: %v", err)
+	}
+	for tr := node; tr != nil; tr = tr.Parent {
+		err = dotWriter.writer.Write(solutionNodeStatement(tr.Number))
+		if err != nil {
+			return fmt.Errorf("error describing solution path: %v", err)
+		}
+		if tr.Parent != nil {
+			err = dotWriter.writer.Write(solutionEdgeStatement(tr.Parent.Number, tr.Number))
+			if err != nil {
+				return fmt.Errorf("error describing solution path: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// WriteLegend describes leaves styles and rules colors in a separate cluster
+func (dotWriter *DotWriter) WriteLegend() error {
+	err := dotWriter.writer.Write(legendStatement())
+	if err != nil {
+		return fmt.Errorf("error describing legend: %v", err)
+	}
+	return nil
+}
+
 func (dotWriter *DotWriter) CreatePNG() error {
-	bytes, err := ioutil.ReadFile(dotWriter.writer.GetGraphFilename())
+	bytes, err := dotWriter.writer.ReadAll()
 	if err != nil {
 		return fmt.Errorf("error reading dot file: %v", err)
 	}
-	graph, err := graphviz.ParseBytes(bytes)
+	return RenderGraph(bytes, PNG, dotWriter.writer.GetPicFilename())
+}
+
+// graphvizInstance is shared by all renderings, graphviz library is not safe for concurrent use
+var graphvizInstance *graphviz.Graphviz
+var graphvizMutex sync.Mutex
+
+// RenderGraph renders DOT description into the image file of the format, png or svg
+func RenderGraph(dot []byte, format string, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("error creating %s file: %v", format, err)
+	}
+	err = RenderGraphTo(dot, format, file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// RenderGraphTo renders DOT description into the image of the format, png or svg
+func RenderGraphTo(dot []byte, format string, writer io.Writer) error {
+	var graphFormat graphviz.Format
+	switch format {
+	case PNG:
+		graphFormat = graphviz.PNG
+	case SVG:
+		graphFormat = graphviz.SVG
+	default:
+		return fmt.Errorf("invalid image format: %s", format)
+	}
+	graphvizMutex.Lock()
+	defer graphvizMutex.Unlock()
+	if graphvizInstance == nil {
+		graphvizInstance = graphviz.New()
+	}
+	graph, err := graphviz.ParseBytes(dot)
 	if err != nil {
 		return fmt.Errorf("error parsing dot file: %v", err)
 	}
-	if err := graphviz.New().RenderFilename(graph, graphviz.PNG, dotWriter.writer.GetPicFilename()); err != nil {
-		return fmt.Errorf("error writing to png file: %v", err)
+	defer graph.Close()
+	if err := graphvizInstance.Render(graph, graphFormat, writer); err != nil {
+		return fmt.Errorf("error writing %s: %v", format, err)
 	}
 	return nil
 }
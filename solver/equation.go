@@ -3,6 +3,8 @@ package solver
 import (
 	"fmt"
 	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"strings"
+	"unicode/utf8"
 )
 
 type Equation struct {
@@ -15,84 +17,29 @@ type Equation struct {
 const EQUALS = "="
 
 func (equation *Equation) Init(eq string, constAlphabet *Alphabet, varsAlphabet *Alphabet) error {
-	//fmt.Println(constAlphabet.words)
-	//fmt.Print(varsAlphabet.words)
-	var err error
-	isEq, i := checkEquation(eq)
-	if isEq {
-		eqleftPart := eq[0 : i-1]
-		var leftSymbols []symbol.Symbol
-		if eqleftPart == "" {
-			leftSymbols = append(leftSymbols, symbol.Empty())
-		} else {
-			leftSymbols, err = matchWithAlphabetsWithSpace(eqleftPart, constAlphabet, varsAlphabet)
-			if err != nil {
-				return fmt.Errorf("error matching alphabet: %v", err)
-			}
-		}
-		eqRightPart := eq[i+2:]
-		var rightSymbols []symbol.Symbol
-		if eqRightPart == "" {
-			rightSymbols = append(rightSymbols, symbol.Empty())
-		} else {
-			rightSymbols, err = matchWithAlphabetsWithSpace(eqRightPart, constAlphabet, varsAlphabet)
-			if err != nil {
-				return fmt.Errorf("error matching alphabet: %v", err)
-			}
-		}
-		equation.leftLength = len(leftSymbols)
-		equation.leftPart = leftSymbols
-		equation.rightLength = len(rightSymbols)
-		equation.rightPart = rightSymbols
-		return nil
+	leftSymbols, rightSymbols, err := parseEquation(eq, constAlphabet, varsAlphabet, false)
+	if err != nil {
+		return err
 	}
-	return fmt.Errorf("invalid equation: %s", eq)
+	equation.set(leftSymbols, rightSymbols)
+	return nil
 }
 
-func checkEquation(eq string) (bool, int) {
-	eqLen := len(eq) - 1
-	for i := 0; i < eqLen; i++ {
-		if string(eq[i]) == EQUALS && string(eq[i-1]) == SPACE && string(eq[i+1]) == SPACE {
-			return true, i
-		}
-	}
-	return false, 0
+// InitCompact parses equation written without spaces between letters, such as uxab=abux
+func (equation *Equation) InitCompact(eq string, constAlphabet *Alphabet, varsAlphabet *Alphabet) error {
+	leftSymbols, rightSymbols, err := parseEquation(eq, constAlphabet, varsAlphabet, true)
+	if err != nil {
+		return err
+	}
+	equation.set(leftSymbols, rightSymbols)
+	return nil
 }
 
-func matchWithAlphabetsWithSpace(eqPart string, constAlphabet *Alphabet, varsAlphabet *Alphabet) ([]symbol.Symbol, error) {
-	var symbols []symbol.Symbol
-	var word string
-	var err error
-	var matchType int
-	for _, eqSym := range eqPart {
-		eqSymString := string(eqSym)
-		if eqSymString != SPACE {
-			word += eqSymString
-		} else if word != "" {
-			matchType, err = matchWord(word, varsAlphabet, constAlphabet)
-			if err != nil {
-				return symbols, fmt.Errorf("error matching word: %v", err)
-			}
-			symb, err := symbol.NewSymbol(matchType, word)
-			if err != nil {
-				return symbols, fmt.Errorf("error creating symbol: %v", err)
-			}
-			symbols = append(symbols, symb)
-			word = ""
-		}
-	}
-	if word != "" {
-		matchType, err = matchWord(word, varsAlphabet, constAlphabet)
-		if err != nil {
-			return symbols, fmt.Errorf("error matching word: %v", err)
-		}
-		symb, err := symbol.NewSymbol(matchType, word)
-		if err != nil {
-			return symbols, fmt.Errorf("error creating symbol: %v", err)
-		}
-		symbols = append(symbols, symb)
-	}
-	return symbols, nil
+func (equation *Equation) set(leftSymbols []symbol.Symbol, rightSymbols []symbol.Symbol) {
+	equation.leftLength = len(leftSymbols)
+	equation.leftPart = leftSymbols
+	equation.rightLength = len(rightSymbols)
+	equation.rightPart = rightSymbols
 }
 
 func matchWord(word string, varsAlphabet *Alphabet, constAlphabet *Alphabet) (int, error) {
@@ -124,7 +71,7 @@ func matchWord(word string, varsAlphabet *Alphabet, constAlphabet *Alphabet) (in
 }
 
 func findInAlphabet(word string, alphabet *Alphabet) bool {
-	if len(word) <= alphabet.maxWordLength {
+	if utf8.RuneCountInString(word) <= alphabet.maxWordLength {
 		for _, vWord := range alphabet.words {
 			if word == vWord {
 				return true
@@ -134,83 +81,69 @@ func findInAlphabet(word string, alphabet *Alphabet) bool {
 	return false
 }
 
+// matchWithAlphabets splits word written without spaces into letters of alphabets,
+// the split must be unique, otherwise two different splits are reported
 func matchWithAlphabets(eqPart string, constAlphabet *Alphabet, varsAlphabet *Alphabet) ([]symbol.Symbol, error) {
-	eqLen := len(eqPart)
-	var symbols []symbol.Symbol
-	var match bool
-	var matchType int
-	var lastMatchType int
-	var lastMatchedWord string
-	var currentWord string
-	var startIndex int
-	var i int
-	continueSearch := true
-	for {
-		currentWord += string(eqPart[i])
-		match = findInAlphabet(currentWord, varsAlphabet)
-		if match {
-			matchType = symbol.VARIABLE
-		}
-		match = findInAlphabet(currentWord, constAlphabet)
-		if match {
-			matchType = symbol.CONSTANT
-		}
-
-		if symbol.IsEmptyValue(currentWord) {
-			match = true
-			matchType = symbol.EMPTY
-		}
-		nextWordLen := len(currentWord) + 1
-		continueSearch = (nextWordLen <= varsAlphabet.maxWordLength || nextWordLen <= constAlphabet.maxWordLength) && eqLen != i+1
-		//fmt.Println(nextWordLen, varsAlphabet.maxWordLength, constAlphabet.maxWordLength, continueSearch)
-		if match {
-			lastMatchedWord = currentWord
-			lastMatchType = matchType
-			match = false
-			if continueSearch {
-				i++
-			} else {
-				symb, err := symbol.NewSymbol(matchType, currentWord)
-				if err != nil {
-					return symbols, fmt.Errorf("error creating symbol: %v", err)
-				}
-				symbols = append(symbols, symb)
-				currentWord = ""
-				lastMatchedWord = ""
-				lastMatchType = 0
-				i++
-				startIndex = i
-				if i >= eqLen {
-					break
-				}
+	runes := []rune(eqPart)
+	maxLength := max(max(constAlphabet.maxWordLength, varsAlphabet.maxWordLength), 1)
+	// splits[i] is the number of splits of runes[i:], counting stops at two
+	splits := make([]int, len(runes)+1)
+	splits[len(runes)] = 1
+	// lengths[i] keeps lengths of letters runes[i:] may start with, so that the rest can be split
+	lengths := make([][]int, len(runes)+1)
+	for i := len(runes) - 1; i >= 0; i-- {
+		for length := 1; length <= maxLength && i+length <= len(runes); length++ {
+			if splits[i+length] == 0 {
+				continue
 			}
-		} else {
-			if continueSearch {
-				i++
-			} else {
-				if lastMatchedWord == "" {
-					return nil, fmt.Errorf("no match for word: %s", currentWord)
-				}
-				symb, err := symbol.NewSymbol(lastMatchType, lastMatchedWord)
-				if err != nil {
-					return symbols, fmt.Errorf("error creating symbol: %v", err)
-				}
-				symbols = append(symbols, symb)
-				i = startIndex + len(lastMatchedWord)
-				startIndex = i
-				currentWord = ""
-				lastMatchedWord = ""
-				lastMatchType = 0
-				if i >= eqLen {
-					break
+			word := string(runes[i : i+length])
+			_, err := matchWord(word, varsAlphabet, constAlphabet)
+			if err != nil {
+				if findInAlphabet(word, varsAlphabet) && findInAlphabet(word, constAlphabet) {
+					return nil, err
 				}
+				continue
 			}
+			lengths[i] = append(lengths[i], length)
+			splits[i] = min(splits[i]+splits[i+length], 2)
+		}
+	}
+	if splits[0] == 0 {
+		return nil, fmt.Errorf("no split into letters found for word: %s", eqPart)
+	}
+	words := collectSplits(runes, lengths, 0, nil, 2)
+	if splits[0] > 1 {
+		return nil, fmt.Errorf("ambiguous word %s: can be split as '%s' or '%s'", eqPart,
+			strings.Join(words[0], " "), strings.Join(words[1], " "))
+	}
+	var symbols []symbol.Symbol
+	for _, word := range words[0] {
+		matchType, _ := matchWord(word, varsAlphabet, constAlphabet)
+		symb, err := symbol.NewSymbol(matchType, word)
+		if err != nil {
+			return symbols, fmt.Errorf("error creating symbol: %v", err)
 		}
+		symbols = append(symbols, symb)
 	}
-	//fmt.Print(symbols)
 	return symbols, nil
 }
 
+// collectSplits returns up to limit splits of runes[start:], every followed length leads to a split
+func collectSplits(runes []rune, lengths [][]int, start int, prefix []string, limit int) [][]string {
+	if start == len(runes) {
+		return [][]string{append([]string{}, prefix...)}
+	}
+	var result [][]string
+	for _, length := range lengths[start] {
+		word := string(runes[start : start+length])
+		result = append(result, collectSplits(runes, lengths, start+length, append(prefix, word), limit-len(result))...)
+		if len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
 func (equation *Equation) CheckInequality() bool {
 	//equation.Print()
 	if equation.IsRightEmpty() {
@@ -274,8 +207,23 @@ func (equation *Equation) CheckEquality() bool {
 	return true
 }
 
+// CheckSameness checks that equations are equal up to one-to-one renaming of words
 func (equation *Equation) CheckSameness(eq *Equation) bool {
 	var wordsMap = map[string]string{}
+	var reverseWordsMap = map[string]string{}
+	matches := func(sym symbol.Symbol, other symbol.Symbol) bool {
+		if !symbol.IsWord(sym) || !symbol.IsWord(other) {
+			return sym == other
+		}
+		mapped, ok := wordsMap[sym.Value()]
+		reverse, reverseOk := reverseWordsMap[other.Value()]
+		if !ok && !reverseOk {
+			wordsMap[sym.Value()] = other.Value()
+			reverseWordsMap[other.Value()] = sym.Value()
+			return true
+		}
+		return mapped == other.Value() && reverse == sym.Value()
+	}
 	if eq.rightLength == 0 {
 		eq.rightLength++
 		eq.rightPart = append(eq.rightPart, symbol.Empty())
@@ -295,24 +243,10 @@ func (equation *Equation) CheckSameness(eq *Equation) bool {
 			if i == eq.leftLength {
 				return false
 			}
-			if sym != eq.leftPart[i] {
-				if symbol.IsWord(sym) && symbol.IsWord(eq.leftPart[i]) {
-					if wordsMap[sym.Value()] == "" {
-						wordsMap[sym.Value()] = eq.leftPart[i].Value()
-						i++
-					} else {
-						if wordsMap[sym.Value()] != eq.leftPart[i].Value() {
-							return false
-						} else {
-							i++
-						}
-					}
-				} else {
-					return false
-				}
-			} else {
-				i++
+			if !matches(sym, eq.leftPart[i]) {
+				return false
 			}
+			i++
 		}
 	}
 	for i < eq.leftLength && symbol.IsEmpty(eq.leftPart[i]) {
@@ -332,24 +266,10 @@ func (equation *Equation) CheckSameness(eq *Equation) bool {
 			if i == eq.rightLength {
 				return false
 			}
-			if sym != eq.rightPart[i] {
-				if symbol.IsWord(sym) && symbol.IsWord(eq.rightPart[i]) {
-					if wordsMap[sym.Value()] == "" {
-						wordsMap[sym.Value()] = eq.rightPart[i].Value()
-						i++
-					} else {
-						if wordsMap[sym.Value()] != eq.rightPart[i].Value() {
-							return false
-						} else {
-							i++
-						}
-					}
-				} else {
-					return false
-				}
-			} else {
-				i++
+			if !matches(sym, eq.rightPart[i]) {
+				return false
 			}
+			i++
 		}
 	}
 	for i < eq.rightLength && symbol.IsEmpty(eq.rightPart[i]) {
@@ -361,6 +281,51 @@ func (equation *Equation) CheckSameness(eq *Equation) bool {
 	return true
 }
 
+// key returns the same string for equations CheckSameness considers equal:
+// empty symbols are skipped and words are numbered in the order of their first occurrence
+func (equation *Equation) key() string {
+	var builder strings.Builder
+	wordNumbers := map[string]int{}
+	for i, part := range [][]symbol.Symbol{equation.leftPart, equation.rightPart} {
+		if i > 0 {
+			builder.WriteString(EQUALS)
+		}
+		for _, sym := range part {
+			if symbol.IsEmpty(sym) {
+				continue
+			}
+			builder.WriteString(" ")
+			if symbol.IsWord(sym) {
+				if _, ok := wordNumbers[sym.Value()]; !ok {
+					wordNumbers[sym.Value()] = len(wordNumbers)
+				}
+				fmt.Fprintf(&builder, "#%d", wordNumbers[sym.Value()])
+			} else {
+				builder.WriteString(sym.Value())
+			}
+		}
+		builder.WriteString(" ")
+	}
+	return builder.String()
+}
+
+// IsQuadratic checks that every variable occurs in equation at most twice
+func (equation *Equation) IsQuadratic() bool {
+	occurrences := map[symbol.Symbol]int{}
+	for _, part := range [][]symbol.Symbol{equation.leftPart, equation.rightPart} {
+		for _, sym := range part {
+			if !symbol.IsVar(sym) {
+				continue
+			}
+			occurrences[sym]++
+			if occurrences[sym] > 2 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func (equation *Equation) SubstituteVarsWithEmpty() Equation {
 	var resultEquation Equation
 	if equation.IsRightEmpty() {
@@ -417,24 +382,28 @@ func (equation *Equation) Substitute(symbol *symbol.Symbol, newSymbols []symbol.
 	return resultEquation
 }
 
+// Reduce removes the common prefix of equation sides, empty symbols the prefix is followed by are removed too,
+// so the sides of reduced equation start with different symbols
 func (equation *Equation) Reduce() {
-	equation.reduceEmpty()
-	minLen := min(equation.leftLength, equation.rightLength)
-	i := 0
-	for ; i < minLen; i++ {
-		if equation.leftPart[i] == equation.rightPart[i] {
+	for {
+		equation.reduceEmpty()
+		minLen := min(equation.leftLength, equation.rightLength)
+		i := 0
+		for ; i < minLen; i++ {
+			if equation.leftPart[i] == equation.rightPart[i] {
 
-		} else {
-			break
+			} else {
+				break
+			}
+		}
+		if i == 0 {
+			return
 		}
-	}
-	if i > 0 {
 		equation.rightPart = equation.rightPart[i:]
 		equation.rightLength -= i
 		equation.leftPart = equation.leftPart[i:]
 		equation.leftLength -= i
 	}
-	equation.reduceEmpty()
 }
 
 func (equation *Equation) reduceEmpty() {
@@ -476,6 +445,14 @@ func min(first int, second int) int {
 	}
 }
 
+func max(first int, second int) int {
+	if first > second {
+		return first
+	} else {
+		return second
+	}
+}
+
 func (equation *Equation) IsLeftEmpty() bool {
 	return equation.leftLength == 1 &&
 		symbol.IsEmpty(equation.leftPart[0]) || equation.leftLength == 0
@@ -17,7 +17,8 @@ var varsAlph = Alphabet{
 	maxWordLength: 2,
 }
 
-var test1InitEqErrorMessage = "invalid equation: aa"
+var test1InitEqErrorMessage = "1:1: no match found with word: aa\naa\n^\n" +
+	"1:3: expected '=' in equation, got end of input\naa\n  ^"
 
 func Test_InitEq_Error_1(t *testing.T) {
 	var eq Equation
@@ -31,7 +32,7 @@ func Test_InitEq_Error_1(t *testing.T) {
 	}
 }
 
-var test2InitEqErrorMessage = "error matching alphabet: error matching word: no match found with word: o"
+var test2InitEqErrorMessage = "1:5: no match found with word: o\na = o\n    ^"
 
 func Test_InitEq_Error_2(t *testing.T) {
 	var eq Equation
@@ -46,7 +47,7 @@ func Test_InitEq_Error_2(t *testing.T) {
 	}
 }
 
-var test3InitEqErrorMessage = "invalid equation: a=o"
+var test3InitEqErrorMessage = "1:3: no match found with word: o\na=o\n  ^"
 
 func Test_InitEq_Error_3(t *testing.T) {
 	var eq Equation
@@ -172,6 +173,44 @@ func TestEquation_Reduce_2(t *testing.T) {
 	}
 }
 
+func TestEquation_Reduce_3(t *testing.T) {
+	var eq Equation
+	err := eq.Init("a $ b x = a b $ v", &constAlphNew, &varsAlphNew)
+	if err != nil {
+		t.Errorf("TestEquation_Reduce_3 failed: error should be nil: %v", err)
+		return
+	}
+	eq.Reduce()
+	if eq.leftLength != 1 || eq.rightLength != 1 || eq.leftPart[0] != symbol.Var("x") ||
+		eq.rightPart[0] != symbol.Var("v") {
+		t.Errorf("TestEquation_Reduce_3 failed: wrong reduce result : %s", eq.String())
+	}
+}
+
+func TestEquation_IsQuadratic_1(t *testing.T) {
+	tests := []struct {
+		equation  string
+		quadratic bool
+	}{
+		{"x a v = v b x", true},
+		{"x x = a b", true},
+		{"a b = b a", true},
+		{"x x a = b x", false},
+		{"u a u = v u", false},
+	}
+	for _, test := range tests {
+		var eq Equation
+		err := eq.Init(test.equation, &constAlphNew, &varsAlphNew)
+		if err != nil {
+			t.Errorf("TestEquation_IsQuadratic_1 failed: error should be nil: %v", err)
+			continue
+		}
+		if eq.IsQuadratic() != test.quadratic {
+			t.Errorf("TestEquation_IsQuadratic_1 failed: %s: expected: %v", test.equation, test.quadratic)
+		}
+	}
+}
+
 func TestEquation_Substitute_1(t *testing.T) {
 	var eq Equation
 	err := eq.Init("a b x = v b", &constAlphNew, &varsAlphNew)
@@ -259,3 +298,100 @@ func TestEquation_Substitute_3(t *testing.T) {
 		}
 	}
 }
+
+func Test_InitEq_3(t *testing.T) {
+	var eq Equation
+	err := eq.Init("a  a\tux=yi b  p", &constAlph, &varsAlph)
+	if err != nil {
+		fmt.Println(err.Error())
+		t.Errorf("Test_InitEq_3 failed: error shouldn be nil")
+		return
+	}
+	if eq.leftLength != 3 || eq.rightLength != 3 || eq.leftPart[2] != symbol.Var("ux") || eq.rightPart[0] != symbol.Var("yi") {
+		t.Errorf("Test_InitEq_3 failed: wrong eq parsing : ")
+		eq.Print()
+	}
+}
+
+var test4InitEqErrorMessage = "1:3: no match found with word: o\na o = b, q = c\n  ^\n" +
+	"1:8: unexpected ',' in equation\na o = b, q = c\n       ^\n" +
+	"1:10: no match found with word: q\na o = b, q = c\n         ^\n" +
+	"1:12: unexpected second '=' in equation\na o = b, q = c\n           ^"
+
+func Test_InitEq_Error_4(t *testing.T) {
+	var eq Equation
+	err := eq.Init("a o = b, q = c", &constAlph, &varsAlph)
+	if err == nil {
+		t.Errorf("Test_InitEq_Error_4 failed: error shouldn\\'t be nil")
+	} else {
+		if err.Error() != test4InitEqErrorMessage {
+			fmt.Println(err.Error())
+			t.Errorf("Test_InitEq_Error_4 failed: wrong error message")
+		}
+	}
+}
+
+func Test_InitCompactEq_1(t *testing.T) {
+	var eq Equation
+	err := eq.InitCompact("uxab=abux", &constAlph, &varsAlph)
+	if err != nil {
+		fmt.Println(err.Error())
+		t.Errorf("Test_InitCompactEq_1 failed: error should be nil")
+		return
+	}
+	if eq.String() != "ux a b = a b ux " {
+		t.Errorf("Test_InitCompactEq_1 failed: wrong eq parsing: %s", eq.String())
+	}
+}
+
+func Test_InitCompactEq_2(t *testing.T) {
+	var eq Equation
+	err := eq.InitCompact("ayip c = $", &constAlph, &varsAlph)
+	if err != nil {
+		fmt.Println(err.Error())
+		t.Errorf("Test_InitCompactEq_2 failed: error should be nil")
+		return
+	}
+	if eq.String() != "a yi p c = $ " {
+		t.Errorf("Test_InitCompactEq_2 failed: wrong eq parsing: %s", eq.String())
+	}
+}
+
+var testCompactConstAlph = Alphabet{
+	words:         []string{"a", "ab", "ba"},
+	size:          3,
+	maxWordLength: 2,
+}
+var testCompactVarsAlph = Alphabet{
+	words:         []string{"b", "xyz", "x"},
+	size:          3,
+	maxWordLength: 3,
+}
+
+func Test_InitCompactEq_3(t *testing.T) {
+	var eq Equation
+	err := eq.InitCompact("xyzab=bax", &testCompactConstAlph, &testCompactVarsAlph)
+	if err == nil {
+		t.Errorf("Test_InitCompactEq_3 failed: error shouldn\\'t be nil")
+		return
+	}
+	var testInitCompactAmbiguousMessage = "1:1: ambiguous word xyzab: can be split as 'xyz a b' or 'xyz ab'\nxyzab=bax\n^\n" +
+		"1:7: ambiguous word bax: can be split as 'b a x' or 'ba x'\nxyzab=bax\n      ^"
+	if err.Error() != testInitCompactAmbiguousMessage {
+		fmt.Println(err.Error())
+		t.Errorf("Test_InitCompactEq_3 failed: wrong error message")
+	}
+}
+
+var testInitCompactErrorMessage = "1:1: no split into letters found for word: xyab\nxyab = x\n^"
+
+func Test_InitCompactEq_Error_1(t *testing.T) {
+	var eq Equation
+	err := eq.InitCompact("xyab = x", &testCompactConstAlph, &testCompactVarsAlph)
+	if err == nil {
+		t.Errorf("Test_InitCompactEq_Error_1 failed: error shouldn\\'t be nil")
+	} else if err.Error() != testInitCompactErrorMessage {
+		fmt.Println(err.Error())
+		t.Errorf("Test_InitCompactEq_Error_1 failed: wrong error message")
+	}
+}
@@ -0,0 +1,65 @@
+package solver
+
+import (
+	"time"
+)
+
+func (substitution *Substitution) String() string {
+	return substitutionString(&substitution.Symbol, substitution.NewSymbols)
+}
+
+// Root returns the root of the search tree, it is explored step by step with Expand and SolveFrom
+func (solver *Solver) Root() *Node {
+	if solver.tree == nil {
+		solver.tree = &Node{
+			Number: "0",
+			Value:  solver.equation,
+		}
+		solver.tree.Value.Reduce()
+	}
+	return solver.tree
+}
+
+// Expand returns TRUE, FALSE or CYCLED if node is a leaf of the search tree,
+// otherwise it applies the rule matching the node equation and returns children kept in the node
+func (solver *Solver) Expand(node *Node) (string, []*Node) {
+	if node.Leaf != "" && node.Leaf != CUT {
+		return node.Leaf, nil
+	}
+	if solver.checkInequality(node) {
+		node.Leaf = FALSE
+		return node.Leaf, nil
+	}
+	if solver.checkEquality(node) {
+		node.Leaf = TRUE
+		return node.Leaf, nil
+	}
+	if solver.checkHasBeen(node) {
+		return node.Leaf, nil
+	}
+	if node.Children == nil {
+		solver.expand(node)
+	}
+	if len(node.Children) == 0 {
+		node.Leaf = FALSE
+		return node.Leaf, nil
+	}
+	return "", node.Children
+}
+
+// SolveFrom runs search from node as Solve does from the root, nodes counts are counted from node,
+// found solution is the solution of the whole equation
+func (solver *Solver) SolveFrom(node *Node) Result {
+	solver.hasSolution = false
+	solver.cycled = false
+	solver.timedOut = false
+	solver.solutionNode = nil
+	solver.nodesCount = 0
+	solver.maxDepth = 0
+	solver.statistics = newStatistics()
+	node.Children = nil
+	node.Leaf = ""
+	node.Repeats = nil
+	solver.search(node, time.Now())
+	return solver.result
+}
@@ -0,0 +1,202 @@
+package solver
+
+import (
+	"fmt"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"strings"
+	"testing"
+)
+
+// fuzzSymbols are symbols fuzzEquation builds equations of, the last byte values switch to the right side
+var fuzzSymbols = []symbol.Symbol{
+	symbol.Const("a"), symbol.Const("b"),
+	symbol.Var("x"), symbol.Var("y"), symbol.Var("z"),
+	symbol.Empty(),
+	symbol.WordVar("w1"), symbol.WordVar("w2"), symbol.WordVar("w3"),
+}
+
+const fuzzWordsCount = 3
+
+// fuzzEquation decodes equation from bytes, words are used only when withWords is set
+func fuzzEquation(data []byte, withWords bool) Equation {
+	symbolsCount := len(fuzzSymbols)
+	if !withWords {
+		symbolsCount -= fuzzWordsCount
+	}
+	var left, right []symbol.Symbol
+	isRight := false
+	for _, b := range data {
+		index := int(b) % (symbolsCount + 1)
+		if index == symbolsCount {
+			isRight = true
+		} else if isRight {
+			right = append(right, fuzzSymbols[index])
+		} else {
+			left = append(left, fuzzSymbols[index])
+		}
+	}
+	var equation Equation
+	equation.set(left, right)
+	return equation
+}
+
+// fuzzAssignments returns assignments of x, y and z to all words over {a, b} of length at most 1
+func fuzzAssignments() []map[string][]symbol.Symbol {
+	values := words([]string{"a", "b"}, 1)
+	var assignments []map[string][]symbol.Symbol
+	for _, x := range values {
+		for _, y := range values {
+			for _, z := range values {
+				assignment := map[string][]symbol.Symbol{}
+				for variable, value := range map[string][]string{"x": x, "y": y, "z": z} {
+					assignment[variable] = []symbol.Symbol{}
+					for _, constant := range value {
+						assignment[variable] = append(assignment[variable], symbol.Const(constant))
+					}
+				}
+				assignments = append(assignments, assignment)
+			}
+		}
+	}
+	return assignments
+}
+
+func isSolution(equation *Equation, assignment map[string][]symbol.Symbol) bool {
+	return substituteAssignment(equation.leftPart, assignment) == substituteAssignment(equation.rightPart, assignment)
+}
+
+func FuzzEquationInit(f *testing.F) {
+	f.Add("{a, b}", "{x, y}", "x a y = y a x")
+	f.Add("{a}", "{u, v}", "u a v = v a u")
+	f.Add("{}", "{x}", "x = $")
+	f.Add("{ж, a}", "{α}", "α ж = ж α")
+	f.Add("{a,b}", "{x}", "= x x =")
+	f.Fuzz(func(t *testing.T, constants string, variables string, eq string) {
+		constAlphabet, err := parseAlphabet(constants)
+		if err != nil {
+			return
+		}
+		varsAlphabet, err := parseAlphabet(variables)
+		if err != nil {
+			return
+		}
+		var equation Equation
+		err = equation.Init(eq, &constAlphabet, &varsAlphabet)
+		if err != nil {
+			return
+		}
+		var reparsed Equation
+		err = reparsed.Init(equation.String(), &constAlphabet, &varsAlphabet)
+		if err != nil {
+			t.Errorf("FuzzEquationInit failed: printed equation %q can't be parsed: %v", equation.String(), err)
+			return
+		}
+		if reparsed.String() != equation.String() {
+			t.Errorf("FuzzEquationInit failed: equation %q is parsed back as %q", equation.String(), reparsed.String())
+		}
+	})
+}
+
+func FuzzParseAlphabet(f *testing.F) {
+	f.Add("{a, b}")
+	f.Add("{}")
+	f.Add("{ж,α , b}")
+	f.Add("{a,, b")
+	f.Fuzz(func(t *testing.T, alphabetStr string) {
+		alphabet, err := parseAlphabet(alphabetStr)
+		if err != nil {
+			if !strings.Contains(err.Error(), ":") {
+				t.Errorf("FuzzParseAlphabet failed: error without position: %v", err)
+			}
+			return
+		}
+		formatted := fmt.Sprintf("%s%s%s", OPENBR, strings.Join(alphabet.words, COMMA+" "), CLOSEBR)
+		reparsed, err := parseAlphabet(formatted)
+		if err != nil {
+			t.Errorf("FuzzParseAlphabet failed: formatted alphabet %q can't be parsed: %v", formatted, err)
+			return
+		}
+		if strings.Join(reparsed.words, COMMA) != strings.Join(alphabet.words, COMMA) || reparsed.size != alphabet.size {
+			t.Errorf("FuzzParseAlphabet failed: alphabet %q is parsed back as %q", formatted, reparsed.words)
+		}
+		for _, word := range alphabet.words {
+			if !alphabet.Has(word) || word == "" {
+				t.Errorf("FuzzParseAlphabet failed: wrong letter %q of %q", word, alphabetStr)
+			}
+		}
+	})
+}
+
+// FuzzSubstitute checks that equation after substitution x -> newSymbols has solution s
+// if and only if the equation has solution s with x replaced by the value of newSymbols under s
+func FuzzSubstitute(f *testing.F) {
+	f.Add([]byte{2, 0, 3, 6, 3, 0, 2}, []byte{3, 2})
+	f.Add([]byte{0, 2, 6, 2, 1}, []byte{5})
+	f.Add([]byte{6, 0}, []byte{0, 2})
+	f.Fuzz(func(t *testing.T, data []byte, newData []byte) {
+		equation := fuzzEquation(data, false)
+		newSymbols := fuzzEquation(newData, false).leftPart
+		variable := symbol.Symbol(symbol.Var("x"))
+		substituted := equation.Substitute(&variable, newSymbols)
+		for _, assignment := range fuzzAssignments() {
+			composed := map[string][]symbol.Symbol{}
+			for name, value := range assignment {
+				composed[name] = value
+			}
+			composed["x"] = []symbol.Symbol{}
+			for _, sym := range newSymbols {
+				if symbol.IsVar(sym) {
+					composed["x"] = append(composed["x"], assignment[sym.Value()]...)
+				} else if symbol.IsConst(sym) {
+					composed["x"] = append(composed["x"], sym)
+				}
+			}
+			if isSolution(&substituted, assignment) != isSolution(&equation, composed) {
+				t.Errorf("FuzzSubstitute failed: %s with x -> %v gave %s, solutions differ on %v", equation.String(),
+					newSymbols, substituted.String(), assignment)
+				return
+			}
+		}
+	})
+}
+
+// FuzzReduce checks that reduced equation has the same solutions
+func FuzzReduce(f *testing.F) {
+	f.Add([]byte{0, 2, 3, 6, 0, 3, 2})
+	f.Add([]byte{5, 5, 0, 6, 0})
+	f.Add([]byte{6})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		equation := fuzzEquation(data, false)
+		reduced := equation
+		reduced.Reduce()
+		if reduced.leftLength != len(reduced.leftPart) || reduced.rightLength != len(reduced.rightPart) {
+			t.Errorf("FuzzReduce failed: wrong lengths of reduced equation: %s", reduced.String())
+		}
+		for _, assignment := range fuzzAssignments() {
+			if isSolution(&reduced, assignment) != isSolution(&equation, assignment) {
+				t.Errorf("FuzzReduce failed: %s reduced to %s, solutions differ on %v", equation.String(),
+					reduced.String(), assignment)
+				return
+			}
+		}
+	})
+}
+
+// FuzzCheckSameness checks that sameness of equations up to renaming of words is reflexive and symmetric
+func FuzzCheckSameness(f *testing.F) {
+	f.Add([]byte{6, 0, 9, 0, 6}, []byte{7, 0, 9, 0, 7})
+	f.Add([]byte{6, 6, 9, 0}, []byte{7, 8, 9, 0})
+	f.Add([]byte{6, 7, 9, 0}, []byte{7, 7, 9, 0})
+	f.Add([]byte{2, 9}, []byte{2, 5, 9, 5})
+	f.Fuzz(func(t *testing.T, first []byte, second []byte) {
+		firstEquation, secondEquation := fuzzEquation(first, true), fuzzEquation(second, true)
+		same := fuzzEquation(first, true)
+		if !firstEquation.CheckSameness(&same) {
+			t.Errorf("FuzzCheckSameness failed: %s is not the same as itself", firstEquation.String())
+		}
+		if firstEquation.CheckSameness(&secondEquation) != secondEquation.CheckSameness(&firstEquation) {
+			t.Errorf("FuzzCheckSameness failed: sameness of %s and %s is not symmetric", firstEquation.String(),
+				secondEquation.String())
+		}
+	})
+}
@@ -0,0 +1,257 @@
+package solver
+
+import (
+	"fmt"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PeriodicFamily describes values Period^k Prefix for every k >= From, Prefix is a proper prefix of Period
+type PeriodicFamily struct {
+	Period []string
+	Prefix []string
+	From   int
+}
+
+// Value returns the value of the family for k
+func (family *PeriodicFamily) Value(k int) []string {
+	value := make([]string, 0, k*len(family.Period)+len(family.Prefix))
+	for i := 0; i < k; i++ {
+		value = append(value, family.Period...)
+	}
+	return append(value, family.Prefix...)
+}
+
+func (family *PeriodicFamily) String() string {
+	result := fmt.Sprintf("(%s)^k", strings.Join(family.Period, " "))
+	if len(family.Prefix) > 0 {
+		result += " " + strings.Join(family.Prefix, " ")
+	}
+	return fmt.Sprintf("%s, k >= %d", result, family.From)
+}
+
+// OneVariableSolutions describes all solutions of equation with one variable: the values of Variable are Words,
+// sorted by length, and the values of Family, if it's set; every word is a value if Any is set
+type OneVariableSolutions struct {
+	Variable string
+	Words    [][]string
+	Family   *PeriodicFamily
+	Any      bool
+}
+
+// Shortest returns the shortest value of the variable, it's false if there are no values
+func (solutions *OneVariableSolutions) Shortest() ([]string, bool) {
+	var shortest []string
+	found := solutions.Any
+	if len(solutions.Words) > 0 {
+		shortest, found = solutions.Words[0], true
+	}
+	if solutions.Family != nil {
+		value := solutions.Family.Value(solutions.Family.From)
+		if !found || len(value) < len(shortest) {
+			shortest, found = value, true
+		}
+	}
+	if found && shortest == nil {
+		shortest = []string{}
+	}
+	return shortest, found
+}
+
+// String returns values separated with |, such as x = $ | a b a | (a b)^k a, k >= 2
+func (solutions *OneVariableSolutions) String() string {
+	if solutions.Any {
+		return fmt.Sprintf("%s = any word", solutions.Variable)
+	}
+	var values []string
+	for _, word := range solutions.Words {
+		if len(word) == 0 {
+			values = append(values, symbol.Empty().Value())
+		} else {
+			values = append(values, strings.Join(word, " "))
+		}
+	}
+	if solutions.Family != nil {
+		values = append(values, solutions.Family.String())
+	}
+	if len(values) == 0 {
+		return fmt.Sprintf("%s has no values", solutions.Variable)
+	}
+	return fmt.Sprintf("%s = %s", solutions.Variable, strings.Join(values, " | "))
+}
+
+// oneVariable returns the only variable occurring in equation, it's false if there are no variables or several
+func (equation *Equation) oneVariable() (string, bool) {
+	variables := equationVariables(equation)
+	if len(variables) != 1 {
+		return "", false
+	}
+	return variables[0], true
+}
+
+// countSymbols returns the number of variable and constant occurrences in equation side
+func countSymbols(part []symbol.Symbol) (int, int) {
+	vars, constants := 0, 0
+	for _, sym := range part {
+		if symbol.IsVar(sym) {
+			vars++
+		} else if symbol.IsConst(sym) {
+			constants++
+		}
+	}
+	return vars, constants
+}
+
+// primitiveRoot returns the shortest word, which word is a power of
+func primitiveRoot(word []string) []string {
+	for length := 1; length < len(word); length++ {
+		if len(word)%length != 0 {
+			continue
+		}
+		root := true
+		for i := length; i < len(word) && root; i++ {
+			root = word[i] == word[i-length]
+		}
+		if root {
+			return word[:length]
+		}
+	}
+	return word
+}
+
+// oneVariableSolutions finds all values of the only variable of equation. After reduction one side starts
+// with the variable and the other one with constants, so every value is a prefix of the infinite power
+// of these constants, that is Period^k Prefix, where Period is their primitive root; the length equation leaves one value at most,
+// unless the variable occurs on both sides the same number of times
+func oneVariableSolutions(equation Equation, variable string) OneVariableSolutions {
+	solutions := OneVariableSolutions{Variable: variable}
+	equation.Reduce()
+	left, right := equation.leftPart, equation.rightPart
+	if equation.IsLeftEmpty() {
+		left = nil
+	}
+	if equation.IsRightEmpty() {
+		right = nil
+	}
+	if len(right) > 0 && symbol.IsVar(right[0]) {
+		left, right = right, left
+	}
+	if len(left) == 0 || !symbol.IsVar(left[0]) {
+		solutions.Any = len(left) == 0 && len(right) == 0
+		return solutions
+	}
+	var period []string
+	for _, sym := range right {
+		if symbol.IsVar(sym) {
+			break
+		}
+		if symbol.IsConst(sym) {
+			period = append(period, sym.Value())
+		}
+	}
+	period = primitiveRoot(period)
+	holds := func(value []string) bool {
+		symbols := make([]symbol.Symbol, len(value))
+		for i, constant := range value {
+			symbols[i] = symbol.Const(constant)
+		}
+		values := map[string][]symbol.Symbol{variable: symbols}
+		return substituteAssignment(left, values) == substituteAssignment(right, values)
+	}
+	leftVars, leftConstants := countSymbols(left)
+	rightVars, rightConstants := countSymbols(right)
+	if leftVars != rightVars {
+		difference := rightConstants - leftConstants
+		if difference%(leftVars-rightVars) != 0 || difference/(leftVars-rightVars) < 0 {
+			return solutions
+		}
+		length := difference / (leftVars - rightVars)
+		if length > 0 && len(period) == 0 {
+			return solutions
+		}
+		value := make([]string, length)
+		for i := range value {
+			value[i] = period[i%len(period)]
+		}
+		if holds(value) {
+			solutions.Words = [][]string{value}
+		}
+		return solutions
+	}
+	if leftConstants != rightConstants {
+		return solutions
+	}
+	// for k >= stableExponent variable blocks are longer than all constants, the constants are at the same
+	// places relative to the blocks for every such k, so the equation holds either for all of them or for none
+	stableExponent := (leftConstants+rightConstants)/len(period) + 2
+	for prefixLength := 0; prefixLength < len(period); prefixLength++ {
+		family := PeriodicFamily{Period: period, Prefix: period[:prefixLength], From: stableExponent + 1}
+		for k := stableExponent; k >= 0 && holds(family.Value(k)); k-- {
+			family.From = k
+		}
+		for k := 0; k < family.From; k++ {
+			if value := family.Value(k); holds(value) {
+				solutions.Words = append(solutions.Words, value)
+			}
+		}
+		if family.From <= stableExponent {
+			// equations with one variable have one periodic family of solutions at most
+			solutions.Family = &family
+		}
+	}
+	sort.SliceStable(solutions.Words, func(i, j int) bool {
+		return len(solutions.Words[i]) < len(solutions.Words[j])
+	})
+	return solutions
+}
+
+// solveOneVariable answers equation with one variable without exploring the tree, the root is the only node
+func (solver *Solver) solveOneVariable(node *Node, start time.Time) {
+	solutions := oneVariableSolutions(node.Value, solver.oneVariable)
+	value, found := solutions.Shortest()
+	var solution map[string][]string
+	if found {
+		solution = map[string][]string{solver.oneVariable: value}
+	}
+	solver.answerRoot(node, start, solution)
+	solver.result.OneVariable = &solutions
+}
+
+// answerRoot makes the root a TRUE leaf if solution is set and a FALSE leaf otherwise and fills result,
+// it's used when equation is answered without exploring the tree; variables missing in solution are empty
+func (solver *Solver) answerRoot(node *Node, start time.Time, solution map[string][]string) {
+	solver.dotWriter.WriteNode(node)
+	solver.nodesCount++
+	solver.result = Result{
+		Equation:   solver.equation.String(),
+		Algorithm:  solver.algorithm,
+		Answer:     FALSE,
+		NodesCount: solver.nodesCount,
+		Statistics: solver.statistics,
+	}
+	if solution == nil {
+		node.Leaf = FALSE
+		falseNode := &FalseNode{number: "F_" + node.Number}
+		solver.dotWriter.WriteInfoNode(falseNode)
+		solver.dotWriter.WriteInfoEdge(node, falseNode)
+		solver.result.Duration = time.Since(start)
+		return
+	}
+	node.Leaf = TRUE
+	trueNode := &TrueNode{number: "T_" + node.Number}
+	solver.dotWriter.WriteInfoNode(trueNode)
+	solver.dotWriter.WriteInfoEdge(node, trueNode)
+	solver.dotWriter.WriteSolutionPath(node, trueNode)
+	solver.hasSolution = true
+	solver.result.Answer = TRUE
+	solver.result.Solution = map[string][]string{}
+	for _, word := range solver.varsAlph.words {
+		solver.result.Solution[word] = []string{}
+		if value, ok := solution[word]; ok {
+			solver.result.Solution[word] = value
+		}
+	}
+	solver.result.Duration = time.Since(start)
+}
@@ -0,0 +1,113 @@
+package solver
+
+import (
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func Test_Solve_OneVariable_1(t *testing.T) {
+	tests := []struct {
+		equation  string
+		answer    string
+		solutions string
+	}{
+		{"x b a = a b x", TRUE, "x = (a b)^k a, k >= 0"},
+		{"a x = x b", FALSE, "x has no values"},
+		{"x x = a a a a", TRUE, "x = a a"},
+		{"x a x = a x a", TRUE, "x = a"},
+		{"a a a x = x a a a", TRUE, "x = (a)^k, k >= 0"},
+		{"x $ = $ x", TRUE, "x = any word"},
+		{"a x = x", FALSE, "x has no values"},
+		{"x a x b = a x b x", TRUE, "x = $"},
+		{"x b x = b a b b b a b", TRUE, "x = b a b"},
+	}
+	for _, test := range tests {
+		var solver Solver
+		err := solver.InitWithOptions("Standard", "{a, b}", "{x, y}", test.equation, Options{DiscardGraph: true})
+		if err != nil {
+			t.Errorf("Test_Solve_OneVariable_1 error should be nil: %v", err)
+			continue
+		}
+		answer, _, err := solver.Solve()
+		if err != nil || answer != test.answer {
+			t.Errorf("Test_Solve_OneVariable_1 failed: %s: expected: %s, but got: %s, %v", test.equation, test.answer,
+				answer, err)
+		}
+		result := solver.GetResult()
+		if result.OneVariable == nil || result.OneVariable.String() != test.solutions || result.NodesCount != 1 {
+			t.Errorf("Test_Solve_OneVariable_1 failed: %s: expected solutions: %s, but got: %v", test.equation,
+				test.solutions, result.OneVariable)
+			continue
+		}
+		if answer == TRUE && !checkSolution(test.equation, result.Solution) {
+			t.Errorf("Test_Solve_OneVariable_1 failed: %s: wrong solution: %v", test.equation, result.Solution)
+		}
+	}
+	var solver Solver
+	err := solver.InitWithOptions("Standard", "{a, b}", "{x}", "x b a = a b x",
+		Options{DiscardGraph: true, GeneralSearch: true})
+	if err != nil {
+		t.Errorf("Test_Solve_OneVariable_1 error should be nil: %v", err)
+		return
+	}
+	answer, _, _ := solver.Solve()
+	if answer != TRUE || solver.GetResult().OneVariable != nil || solver.GetResult().NodesCount == 1 {
+		t.Errorf("Test_Solve_OneVariable_1 failed: general search should explore the tree, but got: %s", answer)
+	}
+}
+
+// hasValue checks that value is one of the described values
+func hasValue(solutions *OneVariableSolutions, value []string) bool {
+	if solutions.Any {
+		return true
+	}
+	key := strings.Join(value, " ")
+	for _, word := range solutions.Words {
+		if strings.Join(word, " ") == key {
+			return true
+		}
+	}
+	if solutions.Family != nil {
+		for k := solutions.Family.From; k <= len(value); k++ {
+			if strings.Join(solutions.Family.Value(k), " ") == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Test_OneVariableSolutions_1 compares solutions of random equations with all short words
+func Test_OneVariableSolutions_1(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	letters := []symbol.Symbol{symbol.Var("x"), symbol.Const("a"), symbol.Const("b"), symbol.Const("a")}
+	side := func() []symbol.Symbol {
+		part := []symbol.Symbol{symbol.Empty()}
+		for length := random.Intn(9); len(part) <= length; {
+			part = append(part, letters[random.Intn(len(letters))])
+		}
+		return part
+	}
+	values := words([]string{"a", "b"}, 6)
+	for i := 0; i < 1000; i++ {
+		var equation Equation
+		equation.set(side(), side())
+		if _, ok := equation.oneVariable(); !ok {
+			continue
+		}
+		solutions := oneVariableSolutions(equation, "x")
+		for _, value := range values {
+			symbols := make([]symbol.Symbol, len(value))
+			for j, constant := range value {
+				symbols[j] = symbol.Const(constant)
+			}
+			if isSolution(&equation, map[string][]symbol.Symbol{"x": symbols}) != hasValue(&solutions, value) {
+				t.Errorf("Test_OneVariableSolutions_1 failed: %s: x = %v, but got: %s", equation.String(), value,
+					solutions.String())
+				break
+			}
+		}
+	}
+}
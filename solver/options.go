@@ -0,0 +1,40 @@
+package solver
+
+// Options describes how the solver explores the tree and writes the graph
+type Options struct {
+	FullGraph  bool
+	MakePng    bool
+	CycleRange int
+	OutputDir  string
+	// OutputFiles keeps files created during the run, graphs of solvers sharing it get unique filenames,
+	// solvers which don't set it share one for the whole process
+	OutputFiles *OutputFiles
+	// Gzip makes graph description gzip-compressed
+	Gzip bool
+	// PartSize is the maximum size of one graph description file in bytes, 0 means no limit;
+	// with Gzip it limits the uncompressed description
+	PartSize int64
+	// PngNodeLimit is the maximum number of nodes png is created for, 0 means no limit
+	PngNodeLimit int
+	// ImplicitAlphabets makes equation words, which are not declared in alphabets, variables or constants
+	// by convention, alphabets may be empty strings then
+	ImplicitAlphabets bool
+	// VarPrefix is the prefix of implicit variables, words starting with upper-case letter are variables if it's empty
+	VarPrefix string
+	// Compact makes equation letters be written without spaces, words are split into letters of alphabets
+	Compact bool
+	// DiscardGraph makes solver explore the tree without writing graph description
+	DiscardGraph bool
+	// MemoryGraph makes solver keep graph description in memory instead of a file, it's returned by GetGraph
+	MemoryGraph bool
+	// MemoryGraphNodeLimit is the maximum number of nodes of graph kept in memory, the graph is dropped
+	// once more nodes are written, 0 means no limit
+	MemoryGraphNodeLimit int
+	// GeneralSearch makes solver explore the tree of equation with one variable or two periodic variables
+	// instead of finding all its solutions
+	GeneralSearch bool
+	// PreChecks are run before the search, DefaultPreChecks are run if it's nil
+	PreChecks []PreCheck
+	// NoPreChecks disables pre-checks
+	NoPreChecks bool
+}
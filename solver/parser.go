@@ -0,0 +1,334 @@
+package solver
+
+import (
+	"fmt"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"strings"
+	"unicode"
+)
+
+const (
+	tokenWord    = 1
+	tokenOpenBr  = 2
+	tokenCloseBr = 3
+	tokenComma   = 4
+	tokenEquals  = 5
+	tokenEOF     = 6
+)
+
+var tokensNames = map[int]string{
+	tokenWord:    "word",
+	tokenOpenBr:  fmt.Sprintf("'%s'", OPENBR),
+	tokenCloseBr: fmt.Sprintf("'%s'", CLOSEBR),
+	tokenComma:   fmt.Sprintf("'%s'", COMMA),
+	tokenEquals:  fmt.Sprintf("'%s'", EQUALS),
+	tokenEOF:     "end of input",
+}
+
+type token struct {
+	kind   int
+	value  string
+	line   int
+	column int
+}
+
+func (tok token) String() string {
+	if tok.kind == tokenWord {
+		return fmt.Sprintf("word '%s'", tok.value)
+	}
+	return tokensNames[tok.kind]
+}
+
+// Problem is one error found in the input, line and column start from 1 and count runes
+type Problem struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// ParseError lists all problems found in the input
+type ParseError struct {
+	Source   string
+	Problems []Problem
+}
+
+func (parseError *ParseError) add(tok token, format string, args ...interface{}) {
+	parseError.Problems = append(parseError.Problems, Problem{
+		Line:    tok.line,
+		Column:  tok.column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (parseError *ParseError) orNil() error {
+	if len(parseError.Problems) == 0 {
+		return nil
+	}
+	return parseError
+}
+
+// snippet returns source line with a caret under the column
+func (parseError *ParseError) snippet(line int, column int) string {
+	lines := strings.Split(parseError.Source, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	sourceLine := []rune(lines[line-1])
+	var caret strings.Builder
+	for i := 0; i < column-1 && i < len(sourceLine); i++ {
+		if sourceLine[i] == '\t' {
+			caret.WriteRune('\t')
+		} else {
+			caret.WriteRune(' ')
+		}
+	}
+	caret.WriteRune('^')
+	return fmt.Sprintf("%s\n%s", string(sourceLine), caret.String())
+}
+
+func (parseError *ParseError) Error() string {
+	var messages []string
+	for _, problem := range parseError.Problems {
+		messages = append(messages, fmt.Sprintf("%d:%d: %s\n%s", problem.Line, problem.Column, problem.Message,
+			parseError.snippet(problem.Line, problem.Column)))
+	}
+	return strings.Join(messages, "\n")
+}
+
+func isSpecialRune(r rune) bool {
+	return string(r) == OPENBR || string(r) == CLOSEBR || string(r) == COMMA || string(r) == EQUALS
+}
+
+// tokenize splits input into words and special symbols, any whitespace separates words
+func tokenize(input string) []token {
+	var tokens []token
+	line, column := 1, 0
+	var word []rune
+	var wordColumn int
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, token{kind: tokenWord, value: string(word), line: line, column: wordColumn})
+			word = nil
+		}
+	}
+	for _, r := range input {
+		column++
+		switch {
+		case r == '\n':
+			flushWord()
+			line++
+			column = 0
+		case unicode.IsSpace(r):
+			flushWord()
+		case isSpecialRune(r):
+			flushWord()
+			var kind int
+			switch string(r) {
+			case OPENBR:
+				kind = tokenOpenBr
+			case CLOSEBR:
+				kind = tokenCloseBr
+			case COMMA:
+				kind = tokenComma
+			case EQUALS:
+				kind = tokenEquals
+			}
+			tokens = append(tokens, token{kind: kind, value: string(r), line: line, column: column})
+		default:
+			if len(word) == 0 {
+				wordColumn = column
+			}
+			word = append(word, r)
+		}
+	}
+	flushWord()
+	return append(tokens, token{kind: tokenEOF, line: line, column: column + 1})
+}
+
+// parseAlphabet parses alphabet of the form {word(, word)*}, whitespace between tokens is optional
+func parseAlphabet(alphabetStr string) (Alphabet, error) {
+	var alphabet Alphabet
+	parseError := &ParseError{Source: alphabetStr}
+	tokens := tokenize(alphabetStr)
+	i := 0
+	if tokens[i].kind != tokenOpenBr {
+		parseError.add(tokens[i], "expected %s at the beginning of alphabet, got %s", tokensNames[tokenOpenBr], tokens[i])
+	} else {
+		i++
+	}
+	expectWord := true
+	closed := false
+	for ; tokens[i].kind != tokenEOF && !closed; i++ {
+		tok := tokens[i]
+		switch tok.kind {
+		case tokenWord:
+			if !expectWord {
+				parseError.add(tok, "expected %s between letters, got %s", tokensNames[tokenComma], tok)
+			}
+			if alphabet.Has(tok.value) {
+				parseError.add(tok, "duplicate letter in alphabet: %s", tok.value)
+			} else if symbol.IsEmptyValue(tok.value) {
+				parseError.add(tok, "empty symbol can't be a letter of alphabet")
+			} else {
+				alphabet.AddWord(tok.value)
+			}
+			expectWord = false
+		case tokenComma:
+			if expectWord {
+				parseError.add(tok, "empty letter in alphabet")
+			}
+			expectWord = true
+		case tokenCloseBr:
+			if expectWord && alphabet.size > 0 {
+				parseError.add(tok, "empty letter in alphabet")
+			}
+			closed = true
+		default:
+			parseError.add(tok, "unexpected %s in alphabet", tok)
+		}
+	}
+	if !closed {
+		parseError.add(tokens[i], "expected %s at the end of alphabet, got %s", tokensNames[tokenCloseBr], tokens[i])
+	}
+	for ; tokens[i].kind != tokenEOF; i++ {
+		parseError.add(tokens[i], "unexpected %s after alphabet", tokens[i])
+	}
+	return alphabet, parseError.orNil()
+}
+
+// parseEquation parses equation of the form word* = word*, matching every word with alphabets,
+// in compact mode every word is split into letters, an empty side is parsed as the empty symbol
+func parseEquation(eq string, constAlphabet *Alphabet, varsAlphabet *Alphabet,
+	compact bool) ([]symbol.Symbol, []symbol.Symbol, error) {
+	var leftSymbols, rightSymbols []symbol.Symbol
+	parseError := &ParseError{Source: eq}
+	tokens := tokenize(eq)
+	equalsFound := false
+	for _, tok := range tokens {
+		switch tok.kind {
+		case tokenWord:
+			symbols, err := matchToken(tok, constAlphabet, varsAlphabet, compact)
+			if err != nil {
+				parseError.add(tok, "%v", err)
+				continue
+			}
+			if equalsFound {
+				rightSymbols = append(rightSymbols, symbols...)
+			} else {
+				leftSymbols = append(leftSymbols, symbols...)
+			}
+		case tokenEquals:
+			if equalsFound {
+				parseError.add(tok, "unexpected second %s in equation", tok)
+			}
+			equalsFound = true
+		case tokenEOF:
+			if !equalsFound {
+				parseError.add(tok, "expected %s in equation, got %s", tokensNames[tokenEquals], tok)
+			}
+		default:
+			parseError.add(tok, "unexpected %s in equation", tok)
+		}
+	}
+	if len(leftSymbols) == 0 {
+		leftSymbols = append(leftSymbols, symbol.Empty())
+	}
+	if len(rightSymbols) == 0 {
+		rightSymbols = append(rightSymbols, symbol.Empty())
+	}
+	return leftSymbols, rightSymbols, parseError.orNil()
+}
+
+func matchToken(tok token, constAlphabet *Alphabet, varsAlphabet *Alphabet, compact bool) ([]symbol.Symbol, error) {
+	if compact {
+		return matchWithAlphabets(tok.value, constAlphabet, varsAlphabet)
+	}
+	matchType, err := matchWord(tok.value, varsAlphabet, constAlphabet)
+	if err != nil {
+		return nil, err
+	}
+	symb, err := symbol.NewSymbol(matchType, tok.value)
+	if err != nil {
+		return nil, fmt.Errorf("error creating symbol: %v", err)
+	}
+	return []symbol.Symbol{symb}, nil
+}
+
+// isImplicitVar checks whether word is a variable by convention: it starts with varPrefix,
+// or, if varPrefix is empty, it starts with an upper-case letter
+func isImplicitVar(word string, varPrefix string) bool {
+	if varPrefix != "" {
+		return strings.HasPrefix(word, varPrefix)
+	}
+	for _, r := range word {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+// inferAlphabets adds equation words, which are not declared in alphabets, to variables or constants by convention
+func inferAlphabets(eq string, constAlphabet *Alphabet, varsAlphabet *Alphabet, varPrefix string) {
+	for _, tok := range tokenize(eq) {
+		if tok.kind != tokenWord || symbol.IsEmptyValue(tok.value) ||
+			constAlphabet.Has(tok.value) || varsAlphabet.Has(tok.value) {
+			continue
+		}
+		if isImplicitVar(tok.value, varPrefix) {
+			varsAlphabet.AddWord(tok.value)
+		} else {
+			constAlphabet.AddWord(tok.value)
+		}
+	}
+}
+
+// parseAssignment parses assignment of the form var = const*(, var = const*)*, an empty value is the empty word
+func parseAssignment(assignment string, constAlphabet *Alphabet, varsAlphabet *Alphabet,
+	compact bool) (map[string][]symbol.Symbol, error) {
+	values := map[string][]symbol.Symbol{}
+	parseError := &ParseError{Source: assignment}
+	tokens := tokenize(assignment)
+	for i := 0; tokens[i].kind != tokenEOF; {
+		variable := tokens[i]
+		if variable.kind != tokenWord {
+			parseError.add(variable, "expected variable, got %s", variable)
+			for ; tokens[i].kind != tokenEOF && tokens[i].kind != tokenComma; i++ {
+			}
+			if tokens[i].kind == tokenComma {
+				i++
+			}
+			continue
+		}
+		if !varsAlphabet.Has(variable.value) {
+			parseError.add(variable, "unknown variable: %s", variable.value)
+		} else if _, ok := values[variable.value]; ok {
+			parseError.add(variable, "duplicate variable: %s", variable.value)
+		}
+		i++
+		if tokens[i].kind != tokenEquals {
+			parseError.add(tokens[i], "expected %s after variable, got %s", tokensNames[tokenEquals], tokens[i])
+		} else {
+			i++
+		}
+		value := []symbol.Symbol{}
+		for ; tokens[i].kind == tokenWord; i++ {
+			symbols, err := matchToken(tokens[i], constAlphabet, &Alphabet{}, compact)
+			if err != nil {
+				parseError.add(tokens[i], "%v", err)
+				continue
+			}
+			for _, sym := range symbols {
+				if !symbol.IsEmpty(sym) {
+					value = append(value, sym)
+				}
+			}
+		}
+		values[variable.value] = value
+		if tokens[i].kind == tokenComma {
+			i++
+		} else if tokens[i].kind != tokenEOF {
+			parseError.add(tokens[i], "expected %s between values, got %s", tokensNames[tokenComma], tokens[i])
+			i++
+		}
+	}
+	return values, parseError.orNil()
+}
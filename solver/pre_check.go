@@ -0,0 +1,119 @@
+package solver
+
+import (
+	"fmt"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+)
+
+// PreCheck is a cheap check run before the search, equation it proves to have no solutions
+// is answered FALSE without exploring the tree
+type PreCheck interface {
+	GetName() string
+	// Check returns the reason equation with the sides has no solutions, it's empty if check can't tell,
+	// the sides must not be changed
+	Check(left []symbol.Symbol, right []symbol.Symbol) string
+}
+
+// DefaultPreChecks returns pre-checks run unless options set others
+func DefaultPreChecks() []PreCheck {
+	return []PreCheck{LengthCheck{}}
+}
+
+// LengthCheck compares lengths of the sides and numbers of every constant on them: the number of a constant
+// on each side is the number of its occurrences plus the sum of its numbers in values of variables
+// times the numbers of variables occurrences, so numbers of constants in values must satisfy a linear
+// equation per constant; equation has no solutions if any of them has no non-negative integer solution
+type LengthCheck struct{}
+
+func (check LengthCheck) GetName() string {
+	return "length"
+}
+
+func (check LengthCheck) Check(left []symbol.Symbol, right []symbol.Symbol) string {
+	// differences are the numbers of variables and words occurrences on the left side minus ones on the right side
+	differences := map[string]int{}
+	// constants are the numbers of constants occurrences on the right side minus ones on the left side
+	constants := map[string]int{}
+	var constantsOrder []string
+	length := 0
+	for i, part := range [][]symbol.Symbol{left, right} {
+		sign := 1 - 2*i
+		for _, sym := range part {
+			if symbol.IsVar(sym) || symbol.IsWord(sym) {
+				differences[sym.Value()] += sign
+			} else if symbol.IsConst(sym) {
+				if _, ok := constants[sym.Value()]; !ok {
+					constantsOrder = append(constantsOrder, sym.Value())
+				}
+				constants[sym.Value()] -= sign
+				length -= sign
+			}
+		}
+	}
+	var coefficients []int
+	for _, difference := range differences {
+		if difference != 0 {
+			coefficients = append(coefficients, difference)
+		}
+	}
+	if !hasNonNegativeSolution(coefficients, length) {
+		return "lengths of the sides can't be equal"
+	}
+	for _, constant := range constantsOrder {
+		if !hasNonNegativeSolution(coefficients, constants[constant]) {
+			return fmt.Sprintf("numbers of %s on the sides can't be equal", constant)
+		}
+	}
+	return ""
+}
+
+// hasNonNegativeSolution checks that the sum of coefficients[i] * n[i] can be equal to target
+// for some non-negative integers n[i]
+func hasNonNegativeSolution(coefficients []int, target int) bool {
+	positive, negative := false, false
+	divisor := 0
+	for _, coefficient := range coefficients {
+		positive = positive || coefficient > 0
+		negative = negative || coefficient < 0
+		divisor = gcd(divisor, abs(coefficient))
+	}
+	if divisor == 0 {
+		return target == 0
+	}
+	if target%divisor != 0 {
+		return false
+	}
+	// terms of different signs compensate each other, so any integer solution can be made non-negative
+	if positive && negative {
+		return true
+	}
+	if negative {
+		target = -target
+	}
+	if target < 0 {
+		return false
+	}
+	reachable := make([]bool, target+1)
+	reachable[0] = true
+	for value := 1; value <= target; value++ {
+		for _, coefficient := range coefficients {
+			if abs(coefficient) <= value && reachable[value-abs(coefficient)] {
+				reachable[value] = true
+				break
+			}
+		}
+	}
+	return reachable[target]
+}
+
+// preCheck runs pre-checks on equation and returns the reason it has no solutions, which is prefixed
+// with the pre-check name, it's empty if pre-checks can't tell
+func (solver *Solver) preCheck() string {
+	for _, check := range solver.preChecks {
+		reason := check.Check(solver.equation.leftPart, solver.equation.rightPart)
+		if reason != "" {
+			return fmt.Sprintf("%s: %s", check.GetName(), reason)
+		}
+	}
+	return ""
+}
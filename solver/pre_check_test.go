@@ -0,0 +1,130 @@
+package solver
+
+import (
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"math/rand"
+	"testing"
+)
+
+func Test_LengthCheck_1(t *testing.T) {
+	constants, _ := parseAlphabet("{a, b}")
+	vars, _ := parseAlphabet("{x, y, z}")
+	tests := []struct {
+		equation string
+		reason   string
+	}{
+		{"x a = b x", "numbers of a on the sides can't be equal"},
+		{"x a y = y b x", "numbers of a on the sides can't be equal"},
+		{"x x = a a a", "lengths of the sides can't be equal"},
+		{"x x y y = a", "lengths of the sides can't be equal"},
+		{"x y = y x b", "lengths of the sides can't be equal"},
+		{"x x a = b y y", "numbers of a on the sides can't be equal"},
+		{"x x a = a y y", ""},
+		{"x x y = a", ""},
+		{"x x a = y b", ""},
+		{"x y z = z y x", ""},
+	}
+	for _, test := range tests {
+		var equation Equation
+		err := equation.Init(test.equation, &constants, &vars)
+		if err != nil {
+			t.Errorf("Test_LengthCheck_1 error should be nil: %v", err)
+			continue
+		}
+		reason := LengthCheck{}.Check(equation.leftPart, equation.rightPart)
+		if reason != test.reason {
+			t.Errorf("Test_LengthCheck_1 failed: %s: expected: %q, but got: %q", test.equation, test.reason, reason)
+		}
+	}
+}
+
+// Test_LengthCheck_2 checks that equations the check rejects have no short solutions
+func Test_LengthCheck_2(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	letters := []symbol.Symbol{symbol.Var("x"), symbol.Var("y"), symbol.Var("z"), symbol.Const("a"), symbol.Const("b")}
+	side := func() []symbol.Symbol {
+		part := []symbol.Symbol{symbol.Empty()}
+		for length := random.Intn(7); len(part) <= length; {
+			part = append(part, letters[random.Intn(len(letters))])
+		}
+		return part
+	}
+	values := words([]string{"a", "b"}, 2)
+	var check LengthCheck
+	rejected := 0
+	for i := 0; i < 500; i++ {
+		var equation Equation
+		equation.set(side(), side())
+		if check.Check(equation.leftPart, equation.rightPart) == "" {
+			continue
+		}
+		rejected++
+		for _, x := range values {
+			for _, y := range values {
+				for _, z := range values {
+					assignment := map[string][]symbol.Symbol{}
+					for variable, value := range map[string][]string{"x": x, "y": y, "z": z} {
+						assignment[variable] = []symbol.Symbol{}
+						for _, constant := range value {
+							assignment[variable] = append(assignment[variable], symbol.Const(constant))
+						}
+					}
+					if isSolution(&equation, assignment) {
+						t.Errorf("Test_LengthCheck_2 failed: %s is rejected, but has solution: %v", equation.String(),
+							assignment)
+					}
+				}
+			}
+		}
+	}
+	if rejected == 0 {
+		t.Errorf("Test_LengthCheck_2 failed: no equations were rejected")
+	}
+}
+
+// rejectingCheck rejects every equation
+type rejectingCheck struct{}
+
+func (check rejectingCheck) GetName() string {
+	return "rejecting"
+}
+
+func (check rejectingCheck) Check(left []symbol.Symbol, right []symbol.Symbol) string {
+	return "every equation is rejected"
+}
+
+func Test_Solve_PreCheck_1(t *testing.T) {
+	tests := []struct {
+		equation string
+		options  Options
+		preCheck string
+	}{
+		{"x a y z = z b y x", Options{}, "length: numbers of a on the sides can't be equal"},
+		{"x a y z = z b y x", Options{NoPreChecks: true}, ""},
+		{"x y z = z y x", Options{PreChecks: []PreCheck{rejectingCheck{}}}, "rejecting: every equation is rejected"},
+		{"x y z = z y x", Options{PreChecks: []PreCheck{rejectingCheck{}}, NoPreChecks: true}, ""},
+	}
+	for _, test := range tests {
+		var solver Solver
+		test.options.DiscardGraph = true
+		err := solver.InitWithOptions("Standard", "{a, b}", "{x, y, z}", test.equation, test.options)
+		if err != nil {
+			t.Errorf("Test_Solve_PreCheck_1 error should be nil: %v", err)
+			continue
+		}
+		answer, _, err := solver.Solve()
+		result := solver.GetResult()
+		if err != nil || result.PreCheck != test.preCheck {
+			t.Errorf("Test_Solve_PreCheck_1 failed: %s: expected pre-check: %q, but got: %q, %v", test.equation,
+				test.preCheck, result.PreCheck, err)
+			continue
+		}
+		if test.preCheck != "" && (answer != FALSE || result.NodesCount != 1) {
+			t.Errorf("Test_Solve_PreCheck_1 failed: %s: the tree should be skipped, but got: %s, %d nodes",
+				test.equation, answer, result.NodesCount)
+		}
+		if test.preCheck == "" && result.NodesCount == 1 {
+			t.Errorf("Test_Solve_PreCheck_1 failed: %s: the tree should be explored", test.equation)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package solver
+
+import (
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"time"
+)
+
+// Result describes one solver run
+type Result struct {
+	Equation   string
+	Algorithm  string
+	Answer     string
+	Duration   time.Duration
+	NodesCount int
+	MaxDepth   int
+	Statistics Statistics
+	// Solution maps every variable to the constants of its value, it is nil if no solution was found
+	Solution map[string][]string
+	// OneVariable describes all solutions of equation with one variable, it is nil if the tree was explored
+	OneVariable *OneVariableSolutions
+	// TwoVariables describes all solutions of equation over two variables without constants,
+	// it is nil if the tree was explored
+	TwoVariables *TwoVariablesSolutions
+	// PreCheck is the reason equation has no solutions found by a pre-check, it is empty if the pre-checks passed
+	PreCheck string
+}
+
+// getSolution composes substitutions on the path from the root to the node,
+// all variables and words left in the node equation are considered empty
+func (solver *Solver) getSolution(node *Node) map[string][]string {
+	values := map[symbol.Symbol][]string{}
+	for tr := node; tr != nil; tr = tr.Parent {
+		if tr.Substitution == nil {
+			continue
+		}
+		var value []string
+		for _, sym := range tr.Substitution.NewSymbols {
+			if symbol.IsConst(sym) {
+				value = append(value, sym.Value())
+			} else {
+				value = append(value, values[sym]...)
+			}
+		}
+		values[tr.Substitution.Symbol] = value
+	}
+	solution := map[string][]string{}
+	for _, word := range solver.varsAlph.words {
+		solution[word] = values[symbol.Var(word)]
+		if solution[word] == nil {
+			solution[word] = []string{}
+		}
+	}
+	return solution
+}
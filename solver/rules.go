@@ -0,0 +1,68 @@
+package solver
+
+const (
+	FIRST_RULE               = 1
+	FIRST_RULE_FINITE        = 2
+	SECOND_RULE_LEFT         = 3
+	SECOND_RULE_RIGHT        = 4
+	SECOND_RULE_LEFT_FINITE  = 5
+	SECOND_RULE_RIGHT_FINITE = 6
+	THIRD_RULE               = 7
+	FOURTH_RULE_LEFT         = 8
+	FOURTH_RULE_RIGHT        = 9
+)
+
+// rules lists all rule kinds in the order they are shown in the legend
+var rules = []int{
+	FIRST_RULE,
+	SECOND_RULE_LEFT,
+	SECOND_RULE_RIGHT,
+	THIRD_RULE,
+	FIRST_RULE_FINITE,
+	SECOND_RULE_LEFT_FINITE,
+	SECOND_RULE_RIGHT_FINITE,
+	FOURTH_RULE_LEFT,
+	FOURTH_RULE_RIGHT,
+}
+
+var rulesNames = map[int]string{
+	FIRST_RULE:               "first",
+	FIRST_RULE_FINITE:        "first finite",
+	SECOND_RULE_LEFT:         "second left",
+	SECOND_RULE_RIGHT:        "second right",
+	SECOND_RULE_LEFT_FINITE:  "second left finite",
+	SECOND_RULE_RIGHT_FINITE: "second right finite",
+	THIRD_RULE:               "third",
+	FOURTH_RULE_LEFT:         "fourth left",
+	FOURTH_RULE_RIGHT:        "fourth right",
+}
+
+var rulesTags = map[int]string{
+	FIRST_RULE:               "1",
+	FIRST_RULE_FINITE:        "1f",
+	SECOND_RULE_LEFT:         "2L",
+	SECOND_RULE_RIGHT:        "2R",
+	SECOND_RULE_LEFT_FINITE:  "2Lf",
+	SECOND_RULE_RIGHT_FINITE: "2Rf",
+	THIRD_RULE:               "3",
+	FOURTH_RULE_LEFT:         "4L",
+	FOURTH_RULE_RIGHT:        "4R",
+}
+
+func RuleName(rule int) string {
+	return rulesNames[rule]
+}
+
+func RuleTag(rule int) string {
+	return rulesTags[rule]
+}
+
+// ruleByTag returns rule of the tag, 0 for unknown tags
+func ruleByTag(tag string) int {
+	for rule, ruleTag := range rulesTags {
+		if ruleTag == tag {
+			return rule
+		}
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package solver
 
 import (
+	"context"
 	"fmt"
 	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
 	"math"
@@ -8,9 +9,12 @@ import (
 	"time"
 )
 
-var timeStart time.Time
-
 const cycle_range = 100
+
+// contextCheckPeriod is the number of nodes explored between checks of the context, the root is checked too
+const contextCheckPeriod = 256
+
+const TIMEOUT = "TIMEOUT"
 const letterBytes = "abcdefghijklmnopqrstuvwxyz"
 
 type Solver struct {
@@ -25,104 +29,155 @@ type Solver struct {
 	dotWriter     DotWriter
 	fullGraph     bool
 	makePng       bool
+	algorithm     string
+	nodesCount    int
+	maxDepth      int
+	solutionNode  *Node
+	result        Result
+	statistics    Statistics
+	tree          *Node
+	timeStart     time.Time
+	ctx           context.Context
+	timedOut      bool
+	// quadratic is set for Standard algorithm type and quadratic equation, the graph of such equation is finite,
+	// so it's explored without cycle range, and nodes equal to any explored node are not expanded again
+	quadratic bool
+	visited   map[string]*Node
+	// oneVariable is the only variable of equation, which is solved by solveOneVariable then, it's empty otherwise
+	oneVariable string
+	// twoVariables is set for equation, which sides are different words over two variables without constants
+	// after common prefix and suffix are removed, it's solved by solveTwoVariables then
+	twoVariables bool
+	preChecks    []PreCheck
 }
 
 func (solver *Solver) Init(algorithmType string, constantsAlph string, varsAlph string, equation string,
 	fullGraph bool, makePng bool, cycleRange int, outputDir string) error {
-	timeStart = time.Now()
+	return solver.InitWithOptions(algorithmType, constantsAlph, varsAlph, equation, Options{
+		FullGraph:  fullGraph,
+		MakePng:    makePng,
+		CycleRange: cycleRange,
+		OutputDir:  outputDir,
+	})
+}
+
+func (solver *Solver) InitWithOptions(algorithmType string, constantsAlph string, varsAlph string, equation string,
+	options Options) error {
+	solver.timeStart = time.Now()
+	solver.ctx = context.Background()
 	var err error
 	intType, err := matchAlgorithmType(algorithmType)
 	if err != nil {
 		return fmt.Errorf("error matching alphabet type: %v", err)
 	}
 	solver.algorithmType = intType
-	constAlphabet, err := solver.parseAlphabet(constantsAlph)
+	solver.algorithm = algorithmType
+	solver.statistics = newStatistics()
+	constAlphabet, varsAlphabet, eq, err := parseInput(constantsAlph, varsAlph, equation, options)
 	if err != nil {
-		return fmt.Errorf("error parsing constants: %v", err)
+		return err
 	}
 	solver.constantsAlph = constAlphabet
-	varsAlphabet, err := solver.parseAlphabet(varsAlph)
-	if err != nil {
-		return fmt.Errorf("error parsing vars: %v", err)
-	}
 	solver.varsAlph = varsAlphabet
-	err = solver.equation.Init(equation, &constAlphabet, &varsAlphabet)
-	if err != nil {
-		return fmt.Errorf("error parsing equation: %v", err)
+	solver.equation = eq
+	solver.quadratic = solver.algorithmType == INFINITE && solver.equation.IsQuadratic()
+	if variable, ok := solver.equation.oneVariable(); ok && !options.GeneralSearch {
+		solver.oneVariable = variable
 	}
-	err = solver.dotWriter.Init(algorithmType, solver.equation.String(), outputDir)
-	if err != nil {
-		return fmt.Errorf("error initing solver: %v", err)
+	if _, ok := twoVariablesSolutions(&solver.equation); ok && !options.GeneralSearch {
+		solver.twoVariables = true
+	}
+	solver.preChecks = options.PreChecks
+	if solver.preChecks == nil {
+		solver.preChecks = DefaultPreChecks()
 	}
-	solver.fullGraph = fullGraph
-	solver.makePng = makePng
-	if cycleRange == 0 {
+	if options.NoPreChecks {
+		solver.preChecks = nil
+	}
+	if options.DiscardGraph {
+		solver.dotWriter.InitDiscard()
+	} else if options.MemoryGraph {
+		solver.dotWriter.InitMemory(options.MemoryGraphNodeLimit)
+	} else {
+		files := options.OutputFiles
+		if files == nil {
+			files = defaultOutputFiles
+		}
+		err = solver.dotWriter.Init(algorithmType, solver.equation.String(), options.OutputDir, files, options.Gzip,
+			options.PartSize, options.PngNodeLimit)
+		if err != nil {
+			return fmt.Errorf("error initing solver: %v", err)
+		}
+	}
+	solver.fullGraph = options.FullGraph
+	solver.makePng = options.MakePng && !options.DiscardGraph && !options.MemoryGraph
+	if options.CycleRange == 0 {
 		solver.cycleRange = cycle_range
 	} else {
-		solver.cycleRange = cycleRange
+		solver.cycleRange = options.CycleRange
 	}
-
-	solver.equation.Print()
-	fmt.Println(algorithmType)
 	return nil
 }
 
-func (solver *Solver) parseAlphabet(alphabetStr string) (Alphabet, error) {
-	var alphabet Alphabet
-	var maxWordLength int
-	lenAlph := len(alphabetStr)
-	if lenAlph < 2 || alphabetStr[0:1] != OPENBR || alphabetStr[lenAlph-1:] != CLOSEBR {
-		return alphabet, fmt.Errorf("invalid constants alphabet: %s", alphabetStr)
-	}
-	alphLetters := alphabetStr[1 : lenAlph-1]
-	lenLetters := len(alphLetters)
-	if lenLetters == 0 {
-		return alphabet, nil
-	}
-	var currentLetter string
-	for i := 0; i < lenLetters; i++ {
-		sym := alphLetters[i]
-		stringSymbol := string(sym)
-		if stringSymbol == COMMA {
-			if currentLetter == "" {
-				return alphabet, fmt.Errorf("empty constant in alphabet: %s", alphabetStr)
-			}
-			if i+1 != lenLetters && string(alphLetters[i+1]) != SPACE {
-				return alphabet, fmt.Errorf("letters must be separated by space: %s", alphabetStr)
-			} else {
-				i++
-			}
-			alphabet.AddWord(currentLetter)
-			if len(currentLetter) > maxWordLength {
-				maxWordLength = len(currentLetter)
-			}
-			currentLetter = ""
-		} else {
-			currentLetter += stringSymbol
+// parseInput parses alphabets and equation, inferring alphabets or splitting compact equation as options say
+func parseInput(constantsAlph string, varsAlph string, equation string,
+	options Options) (Alphabet, Alphabet, Equation, error) {
+	var constAlphabet, varsAlphabet Alphabet
+	var eq Equation
+	var err error
+	if options.ImplicitAlphabets && options.Compact {
+		return constAlphabet, varsAlphabet, eq, fmt.Errorf("implicit alphabets can't be inferred from compact equation")
+	}
+	if !options.ImplicitAlphabets || constantsAlph != "" {
+		constAlphabet, err = parseAlphabet(constantsAlph)
+		if err != nil {
+			return constAlphabet, varsAlphabet, eq, fmt.Errorf("error parsing constants: %v", err)
 		}
 	}
-	if currentLetter == "" {
-		return alphabet, fmt.Errorf("empty constant in alphabet: %s", alphabetStr)
+	if !options.ImplicitAlphabets || varsAlph != "" {
+		varsAlphabet, err = parseAlphabet(varsAlph)
+		if err != nil {
+			return constAlphabet, varsAlphabet, eq, fmt.Errorf("error parsing vars: %v", err)
+		}
+	}
+	if options.ImplicitAlphabets {
+		inferAlphabets(equation, &constAlphabet, &varsAlphabet, options.VarPrefix)
+	}
+	if options.Compact {
+		err = eq.InitCompact(equation, &constAlphabet, &varsAlphabet)
+	} else {
+		err = eq.Init(equation, &constAlphabet, &varsAlphabet)
 	}
-	alphabet.AddWord(currentLetter)
-	if len(currentLetter) > maxWordLength {
-		maxWordLength = len(currentLetter)
+	if err != nil {
+		return constAlphabet, varsAlphabet, eq, fmt.Errorf("error parsing equation: %v", err)
 	}
-	alphabet.maxWordLength = maxWordLength
-	return alphabet, nil
+	return constAlphabet, varsAlphabet, eq, nil
+}
+
+func (solver *Solver) parseAlphabet(alphabetStr string) (Alphabet, error) {
+	return parseAlphabet(alphabetStr)
 }
 
 func (solver *Solver) getAnswer() string {
 	if solver.hasSolution {
 		return "TRUE"
 	}
+	if solver.timedOut {
+		return TIMEOUT
+	}
 	if solver.cycled {
-		return "CYCLED"
+		return CYCLED
 	}
 	return "FALSE"
 }
 
 func (solver *Solver) Solve() (string, time.Duration, error) {
+	return solver.SolveContext(context.Background())
+}
+
+// SolveContext solves equation as Solve does, search stops with TIMEOUT answer when ctx is done
+func (solver *Solver) SolveContext(ctx context.Context) (string, time.Duration, error) {
+	solver.ctx = ctx
 	tree := Node{
 		Number: "0",
 		Value:  solver.equation,
@@ -131,9 +186,19 @@ func (solver *Solver) Solve() (string, time.Duration, error) {
 	if err != nil {
 		return "", 0, fmt.Errorf("error writing DOT description: %v", err)
 	}
-	solver.solve(&tree)
-	result := solver.getAnswer()
-	measuredTime := time.Since(timeStart)
+	solver.tree = &tree
+	if solver.oneVariable != "" {
+		solver.solveOneVariable(&tree, solver.timeStart)
+	} else if solver.twoVariables {
+		solver.solveTwoVariables(&tree, solver.timeStart)
+	} else if reason := solver.preCheck(); reason != "" {
+		solver.answerRoot(&tree, solver.timeStart, nil)
+		solver.result.PreCheck = reason
+	} else {
+		solver.search(&tree, solver.timeStart)
+	}
+	result := solver.result.Answer
+	measuredTime := solver.result.Duration
 	err = solver.dotWriter.EndDOTDescription(solver.makePng)
 	if err != nil {
 		return result, measuredTime, fmt.Errorf("error writing DOT description: %v", err)
@@ -141,6 +206,28 @@ func (solver *Solver) Solve() (string, time.Duration, error) {
 	return result, measuredTime, nil
 }
 
+// search explores tree from node and fills result, duration is measured from start
+func (solver *Solver) search(node *Node, start time.Time) {
+	// rules apply only to sides starting with different symbols, as the sides of every equation produced
+	// by substitution do, otherwise the search of a x y = a y b would answer FALSE
+	node.Value.Reduce()
+	solver.visited = map[string]*Node{}
+	solver.statistics.Quadratic = solver.quadratic
+	solver.solve(node)
+	solver.result = Result{
+		Equation:   solver.equation.String(),
+		Algorithm:  solver.algorithm,
+		Answer:     solver.getAnswer(),
+		Duration:   time.Since(start),
+		NodesCount: solver.nodesCount,
+		MaxDepth:   solver.maxDepth,
+		Statistics: solver.statistics,
+	}
+	if solver.solutionNode != nil {
+		solver.result.Solution = solver.getSolution(solver.solutionNode)
+	}
+}
+
 func (solver *Solver) checkEquality(node *Node) bool {
 	return node.Value.CheckEquality()
 }
@@ -150,9 +237,17 @@ func (solver *Solver) checkInequality(node *Node) bool {
 }
 
 func (solver *Solver) checkHasBeen(node *Node) bool {
+	start := time.Now()
+	defer func() {
+		solver.statistics.SamenessTime += time.Since(start)
+	}()
 	tr := node.Parent
 	for tr != nil {
 		if node.Value.CheckSameness(&tr.Value) {
+			solver.statistics.HasBeenHits++
+			node.Leaf = CYCLED
+			node.Repeats = tr
+			solver.dotWriter.WriteCycledNode(node)
 			solver.dotWriter.WriteDottedEdge(node, tr)
 			return true
 		}
@@ -161,6 +256,32 @@ func (solver *Solver) checkHasBeen(node *Node) bool {
 	return false
 }
 
+// checkVisited checks that node equation is equal to the equation of any explored node,
+// it's used instead of checkHasBeen for quadratic equations, the graph of which is finite
+func (solver *Solver) checkVisited(node *Node) bool {
+	start := time.Now()
+	defer func() {
+		solver.statistics.SamenessTime += time.Since(start)
+	}()
+	key := node.Value.key()
+	visited, ok := solver.visited[key]
+	if !ok {
+		solver.visited[key] = node
+		return false
+	}
+	solver.statistics.HasBeenHits++
+	node.Leaf = CYCLED
+	node.Repeats = visited
+	solver.dotWriter.WriteCycledNode(node)
+	solver.dotWriter.WriteDottedEdge(node, visited)
+	return true
+}
+
+// IsQuadratic tells that equation is solved as quadratic, the answer is TRUE or FALSE then, unless search times out
+func (solver *Solver) IsQuadratic() bool {
+	return solver.quadratic
+}
+
 func randStr(n int) string {
 	rand.Seed(time.Now().UnixNano())
 	b := make([]byte, n)
@@ -178,6 +299,7 @@ func (solver *Solver) getWord() symbol.Word {
 			str := randStr(i)
 			if !solver.wordsAlph.Has(str) {
 				solver.wordsAlph.AddWord(str)
+				solver.statistics.FreshWords++
 				return symbol.WordVar(str)
 			}
 		}
@@ -185,17 +307,51 @@ func (solver *Solver) getWord() symbol.Word {
 	}
 }
 
+// GetGraph returns DOT description of the graph written by the last Solve run
+func (solver *Solver) GetGraph() ([]byte, error) {
+	return solver.dotWriter.writer.ReadAll()
+}
+
+// IsGraphDropped checks that graph kept in memory was dropped by the last Solve run as it had
+// more nodes than MemoryGraphNodeLimit
+func (solver *Solver) IsGraphDropped() bool {
+	return solver.dotWriter.IsDropped()
+}
+
+// GetResult returns description of the last Solve run
+func (solver *Solver) GetResult() Result {
+	return solver.result
+}
+
 func (solver *Solver) solve(node *Node) {
 	solver.dotWriter.WriteNode(node)
+	solver.nodesCount++
+	if depth := len(node.Number) - 1; depth > solver.maxDepth {
+		solver.maxDepth = depth
+	}
+	if length := node.Value.leftLength + node.Value.rightLength; length > solver.statistics.MaxEquationLength {
+		solver.statistics.MaxEquationLength = length
+	}
+	if solver.nodesCount%contextCheckPeriod == 1 && solver.ctx.Err() != nil {
+		solver.timedOut = true
+	}
+	if solver.timedOut {
+		return
+	}
 	if !solver.fullGraph && solver.hasSolution {
 		return
 	}
-	if len(node.Number) > solver.cycleRange {
+	if !solver.quadratic && len(node.Number) > solver.cycleRange {
+		node.Leaf = CUT
+		solver.dotWriter.WriteCutNode(node)
+		solver.statistics.CutNodes++
 		solver.cycled = true
 		return
 	}
 	//fmt.Println(node.Number)
 	if solver.checkInequality(node) {
+		solver.statistics.InequalityLeaves++
+		node.Leaf = FALSE
 		falseNode := &FalseNode{
 			number: "F_" + node.Number,
 		}
@@ -205,92 +361,133 @@ func (solver *Solver) solve(node *Node) {
 		return
 	}
 	if solver.checkEquality(node) {
+		node.Leaf = TRUE
 		trueNode := &TrueNode{
 			number: "T_" + node.Number,
 		}
 		solver.dotWriter.WriteInfoNode(trueNode)
 		solver.dotWriter.WriteInfoEdge(node, trueNode)
+		solver.dotWriter.WriteSolutionPath(node, trueNode)
+		if solver.solutionNode == nil {
+			solver.solutionNode = node
+		}
 		solver.hasSolution = true
 		//fmt.Println("TRUE")
 		//fmt.Println(node.Number)
 		return
 	}
-	if solver.checkHasBeen(node) {
+	if solver.quadratic && solver.checkVisited(node) {
+		return
+	}
+	if !solver.quadratic && solver.checkHasBeen(node) {
 		//fmt.Println("HAS BEEN")
 		//fmt.Println(node.Number)
 		return
 	}
+	solver.expand(node)
+	for _, child := range node.Children {
+		solver.writeEdge(node, child)
+	}
+	//node.Print()
+	//for i, child := range node.Children {
+	//	fmt.Printf(" %d  :", i)
+	//	child.Print()
+	//}
+	for _, child := range node.Children {
+		solver.statistics.RuleNodes[child.Rule]++
+	}
+	for _, child := range node.Children {
+		solver.solve(child)
+	}
+	if len(node.Children) == 0 {
+		solver.statistics.NoChildrenLeaves++
+		node.Leaf = FALSE
+		falseNode := &FalseNode{number: "F_" + node.Number}
+		solver.dotWriter.WriteInfoNode(falseNode)
+		solver.dotWriter.WriteInfoEdge(node, falseNode)
+	}
+}
+
+// expand applies the rule matching the node equation and sets node children
+func (solver *Solver) expand(node *Node) {
 	if solver.algorithmType == FINITE {
 		if solver.checkFirstRuleFinite(&node.Value) {
 			newVals := []symbol.Symbol{node.Value.rightPart[0]}
-			eq := node.Value.Substitute(&node.Value.leftPart[0], newVals)
+			eq := solver.substitute(&node.Value, &node.Value.leftPart[0], newVals)
 			child := Node{
-				Number: "a" + node.Number,
-				Parent: node,
-				Value:  eq,
+				Number:       "a" + node.Number,
+				Parent:       node,
+				Value:        eq,
+				Rule:         FIRST_RULE_FINITE,
+				Substitution: &Substitution{Symbol: node.Value.leftPart[0], NewSymbols: newVals},
 			}
 			node.Children = []*Node{&child}
-			solver.dotWriter.WriteLabelEdge(node, &child, &node.Value.leftPart[0], newVals)
 		}
 		if solver.checkSecondRuleLeftFinite(&node.Value) {
 			newVals := []symbol.Symbol{node.Value.leftPart[0]}
-			eq := node.Value.Substitute(&node.Value.rightPart[0], newVals)
+			eq := solver.substitute(&node.Value, &node.Value.rightPart[0], newVals)
 			child := Node{
-				Number: "b" + node.Number,
-				Parent: node,
-				Value:  eq,
+				Number:       "b" + node.Number,
+				Parent:       node,
+				Value:        eq,
+				Rule:         SECOND_RULE_LEFT_FINITE,
+				Substitution: &Substitution{Symbol: node.Value.rightPart[0], NewSymbols: newVals},
 			}
 			node.Children = []*Node{&child}
-			solver.dotWriter.WriteLabelEdge(node, &child, &node.Value.rightPart[0], newVals)
 		}
 		if solver.checkSecondRuleRightFinite(&node.Value) {
 			newVals := []symbol.Symbol{node.Value.rightPart[0]}
-			eq := node.Value.Substitute(&node.Value.leftPart[0], newVals)
+			eq := solver.substitute(&node.Value, &node.Value.leftPart[0], newVals)
 			child := Node{
-				Number: "c" + node.Number,
-				Parent: node,
-				Value:  eq,
+				Number:       "c" + node.Number,
+				Parent:       node,
+				Value:        eq,
+				Rule:         SECOND_RULE_RIGHT_FINITE,
+				Substitution: &Substitution{Symbol: node.Value.leftPart[0], NewSymbols: newVals},
 			}
 			node.Children = []*Node{&child}
-			solver.dotWriter.WriteLabelEdge(node, &child, &node.Value.leftPart[0], newVals)
 		}
 		if solver.checkFourthRuleLeft(&node.Value) {
 			newValsFirst := []symbol.Symbol{symbol.Empty()}
-			firstEquation := node.Value.Substitute(&node.Value.rightPart[0], newValsFirst)
+			firstEquation := solver.substitute(&node.Value, &node.Value.rightPart[0], newValsFirst)
 			firstChild := Node{
-				Number: "d" + node.Number,
-				Parent: node,
-				Value:  firstEquation,
+				Number:       "d" + node.Number,
+				Parent:       node,
+				Value:        firstEquation,
+				Rule:         FOURTH_RULE_LEFT,
+				Substitution: &Substitution{Symbol: node.Value.rightPart[0], NewSymbols: newValsFirst},
 			}
 			newValsSecond := []symbol.Symbol{node.Value.leftPart[0], node.Value.rightPart[0]}
-			secondEquation := node.Value.Substitute(&node.Value.rightPart[0], newValsSecond)
+			secondEquation := solver.substitute(&node.Value, &node.Value.rightPart[0], newValsSecond)
 			secondChild := Node{
-				Number: "e" + node.Number,
-				Parent: node,
-				Value:  secondEquation,
+				Number:       "e" + node.Number,
+				Parent:       node,
+				Value:        secondEquation,
+				Rule:         FOURTH_RULE_LEFT,
+				Substitution: &Substitution{Symbol: node.Value.rightPart[0], NewSymbols: newValsSecond},
 			}
 			node.Children = []*Node{&firstChild, &secondChild}
-			solver.dotWriter.WriteLabelEdge(node, &firstChild, &node.Value.rightPart[0], newValsFirst)
-			solver.dotWriter.WriteLabelEdge(node, &secondChild, &node.Value.rightPart[0], newValsSecond)
 		}
 		if solver.checkFourthRuleRight(&node.Value) {
 			newValsFirst := []symbol.Symbol{symbol.Empty()}
-			firstEquation := node.Value.Substitute(&node.Value.leftPart[0], newValsFirst)
+			firstEquation := solver.substitute(&node.Value, &node.Value.leftPart[0], newValsFirst)
 			firstChild := Node{
-				Number: "f" + node.Number,
-				Parent: node,
-				Value:  firstEquation,
+				Number:       "f" + node.Number,
+				Parent:       node,
+				Value:        firstEquation,
+				Rule:         FOURTH_RULE_RIGHT,
+				Substitution: &Substitution{Symbol: node.Value.leftPart[0], NewSymbols: newValsFirst},
 			}
 			newValsSecond := []symbol.Symbol{node.Value.rightPart[0], node.Value.leftPart[0]}
-			secondEquation := node.Value.Substitute(&node.Value.leftPart[0], newValsSecond)
+			secondEquation := solver.substitute(&node.Value, &node.Value.leftPart[0], newValsSecond)
 			secondChild := Node{
-				Number: "g" + node.Number,
-				Parent: node,
-				Value:  secondEquation,
+				Number:       "g" + node.Number,
+				Parent:       node,
+				Value:        secondEquation,
+				Rule:         FOURTH_RULE_RIGHT,
+				Substitution: &Substitution{Symbol: node.Value.leftPart[0], NewSymbols: newValsSecond},
 			}
 			node.Children = []*Node{&firstChild, &secondChild}
-			solver.dotWriter.WriteLabelEdge(node, &firstChild, &node.Value.leftPart[0], newValsFirst)
-			solver.dotWriter.WriteLabelEdge(node, &secondChild, &node.Value.leftPart[0], newValsSecond)
 		}
 	}
 	if solver.checkFirstRule(&node.Value) {
@@ -302,11 +499,13 @@ func (solver *Solver) solve(node *Node) {
 			word := solver.getWord()
 			newValsFirst = []symbol.Symbol{node.Value.rightPart[0], word, node.Value.leftPart[0]}
 		}
-		firstEquation := node.Value.Substitute(&node.Value.leftPart[0], newValsFirst)
+		firstEquation := solver.substitute(&node.Value, &node.Value.leftPart[0], newValsFirst)
 		firstChild := Node{
-			Number: node.Number + "1",
-			Parent: node,
-			Value:  firstEquation,
+			Number:       node.Number + "1",
+			Parent:       node,
+			Value:        firstEquation,
+			Rule:         FIRST_RULE,
+			Substitution: &Substitution{Symbol: node.Value.leftPart[0], NewSymbols: newValsFirst},
 		}
 		var newValsSecond []symbol.Symbol
 		if solver.algorithmType == INFINITE {
@@ -316,88 +515,88 @@ func (solver *Solver) solve(node *Node) {
 			word := solver.getWord()
 			newValsSecond = []symbol.Symbol{node.Value.leftPart[0], word, node.Value.rightPart[0]}
 		}
-		secondEquation := node.Value.Substitute(&node.Value.rightPart[0], newValsSecond)
+		secondEquation := solver.substitute(&node.Value, &node.Value.rightPart[0], newValsSecond)
 		secondChild := Node{
-			Number: node.Number + "2",
-			Parent: node,
-			Value:  secondEquation,
+			Number:       node.Number + "2",
+			Parent:       node,
+			Value:        secondEquation,
+			Rule:         FIRST_RULE,
+			Substitution: &Substitution{Symbol: node.Value.rightPart[0], NewSymbols: newValsSecond},
 		}
 		newValsThird := []symbol.Symbol{node.Value.rightPart[0]}
-		thirdEquation := node.Value.Substitute(&node.Value.leftPart[0], newValsThird)
+		thirdEquation := solver.substitute(&node.Value, &node.Value.leftPart[0], newValsThird)
 		thirdChild := Node{
-			Number: node.Number + "3",
-			Parent: node,
-			Value:  thirdEquation,
+			Number:       node.Number + "3",
+			Parent:       node,
+			Value:        thirdEquation,
+			Rule:         FIRST_RULE,
+			Substitution: &Substitution{Symbol: node.Value.leftPart[0], NewSymbols: newValsThird},
 		}
 		node.Children = []*Node{&thirdChild, &firstChild, &secondChild}
-		solver.dotWriter.WriteLabelEdge(node, &thirdChild, &node.Value.leftPart[0], newValsThird)
-		solver.dotWriter.WriteLabelEdge(node, &firstChild, &node.Value.leftPart[0], newValsFirst)
-		solver.dotWriter.WriteLabelEdge(node, &secondChild, &node.Value.rightPart[0], newValsSecond)
 	}
 
 	if solver.checkSecondRuleLeft(&node.Value) {
 		newValsFirst := []symbol.Symbol{symbol.Empty()}
-		firstEquation := node.Value.Substitute(&node.Value.rightPart[0], newValsFirst)
+		firstEquation := solver.substitute(&node.Value, &node.Value.rightPart[0], newValsFirst)
 		firstChild := Node{
-			Number: node.Number + "4",
-			Parent: node,
-			Value:  firstEquation,
+			Number:       node.Number + "4",
+			Parent:       node,
+			Value:        firstEquation,
+			Rule:         SECOND_RULE_LEFT,
+			Substitution: &Substitution{Symbol: node.Value.rightPart[0], NewSymbols: newValsFirst},
 		}
 		var newValsSecond []symbol.Symbol
 		newValsSecond = []symbol.Symbol{node.Value.leftPart[0], node.Value.rightPart[0]}
-		secondEquation := node.Value.Substitute(&node.Value.rightPart[0], newValsSecond)
+		secondEquation := solver.substitute(&node.Value, &node.Value.rightPart[0], newValsSecond)
 		secondChild := Node{
-			Number: node.Number + "5",
-			Parent: node,
-			Value:  secondEquation,
+			Number:       node.Number + "5",
+			Parent:       node,
+			Value:        secondEquation,
+			Rule:         SECOND_RULE_LEFT,
+			Substitution: &Substitution{Symbol: node.Value.rightPart[0], NewSymbols: newValsSecond},
 		}
 		node.Children = []*Node{&firstChild, &secondChild}
-		solver.dotWriter.WriteLabelEdge(node, &firstChild, &node.Value.rightPart[0], newValsFirst)
-		solver.dotWriter.WriteLabelEdge(node, &secondChild, &node.Value.rightPart[0], newValsSecond)
 	}
 	if solver.checkSecondRuleRight(&node.Value) {
 		newValsFirst := []symbol.Symbol{symbol.Empty()}
-		firstEquation := node.Value.Substitute(&node.Value.leftPart[0], newValsFirst)
+		firstEquation := solver.substitute(&node.Value, &node.Value.leftPart[0], newValsFirst)
 		firstChild := Node{
-			Number: node.Number + "6",
-			Parent: node,
-			Value:  firstEquation,
+			Number:       node.Number + "6",
+			Parent:       node,
+			Value:        firstEquation,
+			Rule:         SECOND_RULE_RIGHT,
+			Substitution: &Substitution{Symbol: node.Value.leftPart[0], NewSymbols: newValsFirst},
 		}
 		var newValsSecond []symbol.Symbol
 		newValsSecond = []symbol.Symbol{node.Value.rightPart[0], node.Value.leftPart[0]}
-		secondEquation := node.Value.Substitute(&node.Value.leftPart[0], newValsSecond)
+		secondEquation := solver.substitute(&node.Value, &node.Value.leftPart[0], newValsSecond)
 		secondChild := Node{
-			Number: node.Number + "7",
-			Parent: node,
-			Value:  secondEquation,
+			Number:       node.Number + "7",
+			Parent:       node,
+			Value:        secondEquation,
+			Rule:         SECOND_RULE_RIGHT,
+			Substitution: &Substitution{Symbol: node.Value.leftPart[0], NewSymbols: newValsSecond},
 		}
 		node.Children = []*Node{&firstChild, &secondChild}
-		solver.dotWriter.WriteLabelEdge(node, &firstChild, &node.Value.leftPart[0], newValsFirst)
-		solver.dotWriter.WriteLabelEdge(node, &secondChild, &node.Value.leftPart[0], newValsSecond)
-
 	}
 	if solver.checkThirdRuleLeft(&node.Value) || solver.checkThirdRuleRight(&node.Value) {
-		eq := node.Value.SubstituteVarsWithEmpty()
+		eq := solver.substituteVarsWithEmpty(&node.Value)
 		child := Node{
 			Number: node.Number + "8",
 			Parent: node,
 			Value:  eq,
+			Rule:   THIRD_RULE,
 		}
 		node.Children = []*Node{&child}
-		solver.dotWriter.WriteEdge(node, &child)
 	}
-	//node.Print()
-	//for i, child := range node.Children {
-	//	fmt.Printf(" %d  :", i)
-	//	child.Print()
-	//}
-	for _, child := range node.Children {
-		solver.solve(child)
-	}
-	if len(node.Children) == 0 {
-		falseNode := &FalseNode{number: "F_" + node.Number}
-		solver.dotWriter.WriteInfoNode(falseNode)
-		solver.dotWriter.WriteInfoEdge(node, falseNode)
+}
+
+// writeEdge describes edge from node to its child labeled with the substitution
+func (solver *Solver) writeEdge(node *Node, child *Node) {
+	if child.Substitution == nil {
+		solver.dotWriter.WriteEdge(node, child)
+	} else {
+		solver.dotWriter.WriteLabelEdge(node, child, &child.Substitution.Symbol, child.Substitution.NewSymbols)
 	}
 }
 
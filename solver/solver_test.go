@@ -1,7 +1,9 @@
 package solver
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -19,7 +21,8 @@ func Test_Init_Error_1(t *testing.T) {
 	}
 }
 
-var test2InitErrorMessage = "error parsing constants: invalid constants alphabet: a,c"
+var test2InitErrorMessage = "error parsing constants: 1:1: expected '{' at the beginning of alphabet, got word 'a'\na,c\n^\n" +
+	"1:4: expected '}' at the end of alphabet, got end of input\na,c\n   ^"
 
 func Test_Init_Error_2(t *testing.T) {
 	var solver Solver
@@ -33,7 +36,7 @@ func Test_Init_Error_2(t *testing.T) {
 	}
 }
 
-var test3InitErrorMessage = "error parsing constants: empty constant in alphabet: {a, c, , s}"
+var test3InitErrorMessage = "error parsing constants: 1:8: empty letter in alphabet\n{a, c, , s}\n       ^"
 
 func Test_Init_Error_3(t *testing.T) {
 	var solver Solver
@@ -48,7 +51,8 @@ func Test_Init_Error_3(t *testing.T) {
 	}
 }
 
-var test4InitErrorMessage = "error parsing vars: invalid constants alphabet: b"
+var test4InitErrorMessage = "error parsing vars: 1:1: expected '{' at the beginning of alphabet, got word 'b'\nb\n^\n" +
+	"1:2: expected '}' at the end of alphabet, got end of input\nb\n ^"
 
 func Test_Init_Error_4(t *testing.T) {
 	var solver Solver
@@ -62,7 +66,7 @@ func Test_Init_Error_4(t *testing.T) {
 	}
 }
 
-var test5InitErrorMessage = "error parsing vars: empty constant in alphabet: {a, , s}"
+var test5InitErrorMessage = "error parsing vars: 1:5: empty letter in alphabet\n{a, , s}\n    ^"
 
 func Test_Init_Error_5(t *testing.T) {
 	var solver Solver
@@ -77,7 +81,8 @@ func Test_Init_Error_5(t *testing.T) {
 	}
 }
 
-var test6InitErrorMessage = "error parsing equation: invalid equation: ab"
+var test6InitErrorMessage = "error parsing equation: 1:1: no match found with word: ab\nab\n^\n" +
+	"1:3: expected '=' in equation, got end of input\nab\n  ^"
 
 func Test_Init_Error_6(t *testing.T) {
 	var solver Solver
@@ -92,11 +97,11 @@ func Test_Init_Error_6(t *testing.T) {
 	}
 }
 
-var test7InitErrorMessage = "error parsing constants: letters must be separated by space: {b,n}"
+var test7InitErrorMessage = "error parsing constants: 1:6: expected '}' at the end of alphabet, got end of input\n{b, n\n     ^"
 
 func Test_Init_Error_7(t *testing.T) {
 	var solver Solver
-	err := solver.Init("Standard", "{b,n}", "{a, s}", "ab", false, false, 20, "../output_files")
+	err := solver.Init("Standard", "{b, n", "{a, s}", "ab", false, false, 20, "../output_files")
 	if err == nil {
 		t.Errorf("Test_Init_Error_7 error shouldn\\'t be nil")
 	} else {
@@ -133,8 +138,8 @@ func Test_Solve_2(t *testing.T) {
 		t.Errorf("Test_Solve_2 error should be nil")
 	} else {
 		result, _, _ := solver.Solve()
-		if result != cycledStr {
-			t.Errorf("Test_Solve_2 result should be: %s, but got: %s", cycledStr, result)
+		if result != falseStr {
+			t.Errorf("Test_Solve_2 result should be: %s, but got: %s", falseStr, result)
 		}
 	}
 }
@@ -166,3 +171,443 @@ func Test_Solve_4(t *testing.T) {
 		}
 	}
 }
+
+func substituteSolution(eqPart string, solution map[string][]string) string {
+	var result string
+	for _, word := range strings.Fields(eqPart) {
+		if value, ok := solution[word]; ok {
+			result += strings.Join(value, "")
+		} else if word != "$" {
+			result += word
+		}
+	}
+	return result
+}
+
+func checkSolution(equation string, solution map[string][]string) bool {
+	parts := strings.Split(equation, "=")
+	return substituteSolution(parts[0], solution) == substituteSolution(parts[1], solution)
+}
+
+func Test_Solve_Solution_1(t *testing.T) {
+	var equations = []struct {
+		algorithmType string
+		constants     string
+		vars          string
+		equation      string
+	}{
+		{"Standard", "{a}", "{u, v}", "u a v = v a u"},
+		{"Standard", "{a, b}", "{x, y}", "a b x = y b a"},
+		{"Standard", "{a, b}", "{x, y}", "x a y b = a x b y"},
+		{"Finite", "{a, b}", "{x, y}", "x a b = a y b"},
+		{"Finite", "{a, b}", "{x, y}", "x y a = y x a"},
+	}
+	for _, eq := range equations {
+		var solver Solver
+		err := solver.Init(eq.algorithmType, eq.constants, eq.vars, eq.equation, false, false, 20, "../output_files")
+		if err != nil {
+			t.Errorf("Test_Solve_Solution_1 error should be nil: %v", err)
+			continue
+		}
+		answer, _, _ := solver.Solve()
+		result := solver.GetResult()
+		if answer != trueStr || result.Solution == nil {
+			t.Errorf("Test_Solve_Solution_1 result should be: %s, but got: %s", trueStr, answer)
+			continue
+		}
+		if !checkSolution(eq.equation, result.Solution) {
+			t.Errorf("Test_Solve_Solution_1 failed: wrong solution for %s: %v", eq.equation, result.Solution)
+		}
+		if result.NodesCount == 0 {
+			t.Errorf("Test_Solve_Solution_1 failed: nodes count shouldn't be zero")
+		}
+	}
+}
+
+func Test_Solve_Statistics_1(t *testing.T) {
+	var solver Solver
+	err := solver.InitWithOptions("Standard", "{a, b}", "{u}", "u u a = b u u",
+		Options{CycleRange: 20, OutputDir: "../output_files", GeneralSearch: true, NoPreChecks: true})
+	if err != nil {
+		t.Errorf("Test_Solve_Statistics_1 error should be nil: %v", err)
+		return
+	}
+	solver.Solve()
+	result := solver.GetResult()
+	statistics := result.Statistics
+	if statistics.CutNodes == 0 {
+		t.Errorf("Test_Solve_Statistics_1 failed: cut nodes count shouldn't be zero")
+	}
+	if statistics.RuleNodes[SECOND_RULE_RIGHT] != result.NodesCount-1 {
+		t.Errorf("Test_Solve_Statistics_1 failed: second right rule nodes should be: %d, but got: %d",
+			result.NodesCount-1, statistics.RuleNodes[SECOND_RULE_RIGHT])
+	}
+	if statistics.NoChildrenLeaves+statistics.InequalityLeaves == 0 {
+		t.Errorf("Test_Solve_Statistics_1 failed: FALSE leaves count shouldn't be zero")
+	}
+	if result.MaxDepth != 20 {
+		t.Errorf("Test_Solve_Statistics_1 failed: max depth should be: %d, but got: %d", 20, result.MaxDepth)
+	}
+}
+
+func Test_ParseAlphabet_1(t *testing.T) {
+	var solver Solver
+	alphabet, err := solver.parseAlphabet(" { ab ,c,\td } ")
+	if err != nil {
+		t.Errorf("Test_ParseAlphabet_1 error should be nil: %v", err)
+		return
+	}
+	if alphabet.size != 3 || !alphabet.Has("ab") || !alphabet.Has("c") || !alphabet.Has("d") || alphabet.maxWordLength != 2 {
+		t.Errorf("Test_ParseAlphabet_1 failed: wrong alphabet: %v", alphabet.words)
+	}
+}
+
+var testParseAlphabetErrorMessage = "1:5: duplicate letter in alphabet: a\n{a, a b}\n    ^\n" +
+	"1:7: expected ',' between letters, got word 'b'\n{a, a b}\n      ^"
+
+func Test_ParseAlphabet_Error_1(t *testing.T) {
+	var solver Solver
+	_, err := solver.parseAlphabet("{a, a b}")
+	if err == nil {
+		t.Errorf("Test_ParseAlphabet_Error_1 error shouldn\\'t be nil")
+	} else if err.Error() != testParseAlphabetErrorMessage {
+		fmt.Println(err.Error())
+		t.Errorf("Test_ParseAlphabet_Error_1 failed: wrong error message")
+	}
+}
+
+func Test_InitWithOptions_Implicit_1(t *testing.T) {
+	var equations = []struct {
+		constants string
+		varPrefix string
+		equation  string
+		consts    []string
+		vars      []string
+	}{
+		{"", "", "X a b = a Y b", []string{"a", "b"}, []string{"X", "Y"}},
+		{"", "_", "_x a = a _y", []string{"a"}, []string{"_x", "_y"}},
+		{"{A}", "", "X A = A x", []string{"A", "x"}, []string{"X"}},
+	}
+	for _, eq := range equations {
+		var solver Solver
+		err := solver.InitWithOptions("Standard", eq.constants, "", eq.equation,
+			Options{CycleRange: 20, OutputDir: "../output_files", ImplicitAlphabets: true, VarPrefix: eq.varPrefix})
+		if err != nil {
+			t.Errorf("Test_InitWithOptions_Implicit_1 error should be nil: %v", err)
+			continue
+		}
+		for _, word := range eq.consts {
+			if !solver.constantsAlph.Has(word) {
+				t.Errorf("Test_InitWithOptions_Implicit_1 failed: %s should be a constant", word)
+			}
+		}
+		for _, word := range eq.vars {
+			if !solver.varsAlph.Has(word) {
+				t.Errorf("Test_InitWithOptions_Implicit_1 failed: %s should be a variable", word)
+			}
+		}
+	}
+}
+
+func Test_ParseAlphabet_Unicode_1(t *testing.T) {
+	var solver Solver
+	alphabet, err := solver.parseAlphabet("{α, βγ, ж}")
+	if err != nil {
+		t.Errorf("Test_ParseAlphabet_Unicode_1 error should be nil: %v", err)
+		return
+	}
+	if alphabet.size != 3 || !alphabet.Has("α") || !alphabet.Has("βγ") || !alphabet.Has("ж") || alphabet.maxWordLength != 2 {
+		t.Errorf("Test_ParseAlphabet_Unicode_1 failed: wrong alphabet: %v", alphabet.words)
+	}
+}
+
+var testInitUnicodeErrorMessage = "error parsing equation: 1:7: no match found with word: γ\nα ж = γ\n      ^"
+
+func Test_Init_Unicode_Error_1(t *testing.T) {
+	var solver Solver
+	err := solver.Init("Standard", "{ж}", "{α, β}", "α ж = γ", false, false, 20, "../output_files")
+	if err == nil {
+		t.Errorf("Test_Init_Unicode_Error_1 error shouldn\\'t be nil")
+	} else if err.Error() != testInitUnicodeErrorMessage {
+		fmt.Println(err.Error())
+		t.Errorf("Test_Init_Unicode_Error_1 failed: wrong error message")
+	}
+}
+
+func Test_Solve_Unicode_1(t *testing.T) {
+	var equations = []struct {
+		options  Options
+		equation string
+	}{
+		{Options{}, "α ж β = β ж α"},
+		{Options{Compact: true}, "αжβ=βжα"},
+		{Options{ImplicitAlphabets: true}, "Α ж = ж Α"},
+	}
+	for _, eq := range equations {
+		var solver Solver
+		eq.options.CycleRange = 20
+		eq.options.OutputDir = "../output_files"
+		constants, vars := "{ж}", "{α, β}"
+		if eq.options.ImplicitAlphabets {
+			constants, vars = "", ""
+		}
+		err := solver.InitWithOptions("Standard", constants, vars, eq.equation, eq.options)
+		if err != nil {
+			t.Errorf("Test_Solve_Unicode_1 error should be nil: %v", err)
+			continue
+		}
+		answer, _, _ := solver.Solve()
+		result := solver.GetResult()
+		if answer != trueStr || !checkSolution(solver.equation.String(), result.Solution) {
+			t.Errorf("Test_Solve_Unicode_1 failed: wrong solution for %s: %s, %v", eq.equation, answer, result.Solution)
+		}
+		if !strings.Contains(solver.dotWriter.writer.GetGraphFilename(), sanitizeName(solver.equation.String())) {
+			t.Errorf("Test_Solve_Unicode_1 failed: wrong graph filename: %s", solver.dotWriter.writer.GetGraphFilename())
+		}
+	}
+}
+
+func Test_Verify_1(t *testing.T) {
+	var assignments = []struct {
+		assignment string
+		ok         bool
+		left       string
+		right      string
+	}{
+		{"x = a, y = a", true, "a a b", "a a b"},
+		{"y = b a, x = b a", false, "b a a b", "a b a b"},
+		{"x = $, y = ", true, "a b", "a b"},
+	}
+	for _, assignment := range assignments {
+		ok, left, right, err := Verify("{a, b}", "{x, y}", "x a b = a y b", assignment.assignment, Options{})
+		if err != nil {
+			t.Errorf("Test_Verify_1 error should be nil: %v", err)
+			continue
+		}
+		if ok != assignment.ok || left != assignment.left || right != assignment.right {
+			t.Errorf("Test_Verify_1 failed: wrong result for %s: %v, %s, %s", assignment.assignment, ok, left, right)
+		}
+	}
+}
+
+var testVerifyErrorMessage = "error parsing assignment: 1:1: unknown variable: z\nz = a, x = c b y\n^\n" +
+	"1:12: no match found with word: c\nz = a, x = c b y\n           ^\n" +
+	"1:16: no match found with word: y\nz = a, x = c b y\n               ^"
+
+func Test_Verify_Error_1(t *testing.T) {
+	_, _, _, err := Verify("{a, b}", "{x, y}", "x a b = a y b", "z = a, x = c b y", Options{})
+	if err == nil {
+		t.Errorf("Test_Verify_Error_1 error shouldn\\'t be nil")
+	} else if err.Error() != testVerifyErrorMessage {
+		fmt.Println(err.Error())
+		t.Errorf("Test_Verify_Error_1 failed: wrong error message")
+	}
+	_, _, _, err = Verify("{a, b}", "{x, y}", "x a b = a y b", "x = a", Options{})
+	if err == nil || err.Error() != "no value for variable: y" {
+		t.Errorf("Test_Verify_Error_1 failed: wrong error for missing variable: %v", err)
+	}
+}
+
+func Test_GetTree_1(t *testing.T) {
+	var solver Solver
+	err := solver.Init("Standard", "{a}", "{u, v}", "u a v = v a u", true, false, 20, "../output_files")
+	if err != nil {
+		t.Errorf("Test_GetTree_1 error should be nil: %v", err)
+		return
+	}
+	solver.Solve()
+	tree, err := solver.GetTree()
+	if err != nil {
+		t.Errorf("Test_GetTree_1 error should be nil: %v", err)
+		return
+	}
+	nodes, leaves := 0, map[string]int{}
+	var walk func(node TreeNode)
+	walk = func(node TreeNode) {
+		nodes++
+		leaves[node.Leaf]++
+		for _, child := range node.Children {
+			if child.Rule == "" {
+				t.Errorf("Test_GetTree_1 failed: rule of node %s shouldn't be empty", child.Number)
+			}
+			walk(child)
+		}
+	}
+	walk(tree)
+	if nodes != solver.GetResult().NodesCount {
+		t.Errorf("Test_GetTree_1 failed: tree should have %d nodes, but got: %d", solver.GetResult().NodesCount, nodes)
+	}
+	if !tree.Solution || leaves[TRUE] == 0 || leaves[CYCLED] != solver.GetResult().Statistics.HasBeenHits {
+		t.Errorf("Test_GetTree_1 failed: wrong leaves: %v", leaves)
+	}
+	dot := TreeDOT(tree)
+	if !strings.HasPrefix(dot, DOTHeader) || !strings.Contains(dot, solutionNodeStatement(tree.Number)) {
+		t.Errorf("Test_GetTree_1 failed: wrong DOT description")
+	}
+}
+
+func Test_Expand_1(t *testing.T) {
+	var solver Solver
+	err := solver.InitWithOptions("Standard", "{a}", "{u, v}", "u a v = v a u", Options{DiscardGraph: true})
+	if err != nil {
+		t.Errorf("Test_Expand_1 error should be nil: %v", err)
+		return
+	}
+	root := solver.Root()
+	leaf, children := solver.Expand(root)
+	if leaf != "" || len(children) != 3 || children[0].Rule != FIRST_RULE || children[1].Substitution.String() != "u->vu" {
+		t.Errorf("Test_Expand_1 failed: wrong root children: %s, %v", leaf, children)
+		return
+	}
+	leaf, _ = solver.Expand(children[0])
+	if leaf != TRUE {
+		t.Errorf("Test_Expand_1 failed: first child should be TRUE, but got: %s", leaf)
+	}
+	leaf, children = solver.Expand(children[1])
+	if leaf != "" || len(children) != 2 || children[1].Rule != SECOND_RULE_RIGHT {
+		t.Errorf("Test_Expand_1 failed: wrong second child children: %s, %v", leaf, children)
+		return
+	}
+	leaf, _ = solver.Expand(children[1])
+	if leaf != CYCLED || children[1].Repeats != root {
+		t.Errorf("Test_Expand_1 failed: node should repeat the root, but got: %s", leaf)
+	}
+	result := solver.SolveFrom(children[0])
+	if result.Answer != trueStr || !checkSolution("u a v = v a u", result.Solution) {
+		t.Errorf("Test_Expand_1 failed: wrong search result: %s, %v", result.Answer, result.Solution)
+	}
+}
+
+func Test_SolveContext_1(t *testing.T) {
+	var solver Solver
+	err := solver.InitWithOptions("Standard", "{a, b}", "{x, y, z}", "x a y z = z b y x",
+		Options{FullGraph: true, CycleRange: 200, MemoryGraph: true, NoPreChecks: true})
+	if err != nil {
+		t.Errorf("Test_SolveContext_1 error should be nil: %v", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	answer, _, err := solver.SolveContext(ctx)
+	if err != nil || answer != TIMEOUT {
+		t.Errorf("Test_SolveContext_1 result should be: %s, but got: %s, %v", TIMEOUT, answer, err)
+	}
+	if solver.GetResult().NodesCount != 1 {
+		t.Errorf("Test_SolveContext_1 failed: search should stop at the root, but got nodes: %d",
+			solver.GetResult().NodesCount)
+	}
+	graph, err := solver.GetGraph()
+	if err != nil || !strings.HasPrefix(string(graph), DOTHeader) || !strings.HasSuffix(string(graph), DOTFooter) {
+		t.Errorf("Test_SolveContext_1 failed: wrong graph in memory: %v", err)
+	}
+}
+
+func Test_Solve_CommonPrefix_1(t *testing.T) {
+	for _, algorithmType := range []string{"Standard", "Finite"} {
+		for _, equation := range []string{"b = b y", "a b x = a b a", "a = a"} {
+			var solver Solver
+			err := solver.InitWithOptions(algorithmType, "{a, b}", "{x, y}", equation, Options{DiscardGraph: true})
+			if err != nil {
+				t.Errorf("Test_Solve_CommonPrefix_1 error should be nil: %v", err)
+				continue
+			}
+			answer, _, err := solver.Solve()
+			if err != nil || answer != TRUE {
+				t.Errorf("Test_Solve_CommonPrefix_1 failed: %s %s: expected: %s, but got: %s, %v", algorithmType,
+					equation, TRUE, answer, err)
+			}
+		}
+	}
+}
+
+// Test_Solve_Root_1 checks roots of equations answered without the search are equations as they were parsed
+func Test_Solve_Root_1(t *testing.T) {
+	for _, equation := range []string{"a x = a b", "a x y = a y x", "a x y = a b x b y"} {
+		var solver Solver
+		err := solver.InitWithOptions("Standard", "{a, b}", "{x, y}", equation, Options{DiscardGraph: true})
+		if err != nil {
+			t.Errorf("Test_Solve_Root_1 error should be nil: %v", err)
+			continue
+		}
+		_, _, err = solver.Solve()
+		if err != nil || solver.GetResult().NodesCount != 1 {
+			t.Errorf("Test_Solve_Root_1 failed: %s should be answered without the search: %v", equation, err)
+		}
+		if root := solver.tree.Value.String(); root != equation+" " {
+			t.Errorf("Test_Solve_Root_1 failed: expected root: %s, but got: %s", equation, root)
+		}
+	}
+}
+
+func Test_Solve_Quadratic_CommonPrefix_1(t *testing.T) {
+	tests := []struct {
+		equation string
+		root     string
+	}{
+		{"a x y = a y b", "x y = y b "},
+		{"b y x = b a x", "y x = a x "},
+		{"a b z x = a b y", "z x = y "},
+	}
+	for _, test := range tests {
+		var solver Solver
+		err := solver.InitWithOptions("Standard", "{a, b}", "{x, y, z}", test.equation,
+			Options{FullGraph: true, DiscardGraph: true, GeneralSearch: true, NoPreChecks: true})
+		if err != nil {
+			t.Errorf("Test_Solve_Quadratic_CommonPrefix_1 error should be nil: %v", err)
+			continue
+		}
+		answer, _, err := solver.Solve()
+		if err != nil || answer != TRUE || !checkSolution(test.equation, solver.GetResult().Solution) {
+			t.Errorf("Test_Solve_Quadratic_CommonPrefix_1 failed: %s: expected: %s, but got: %s, %v, %v",
+				test.equation, TRUE, answer, solver.GetResult().Solution, err)
+		}
+		if root := solver.tree.Value.String(); root != test.root {
+			t.Errorf("Test_Solve_Quadratic_CommonPrefix_1 failed: %s: expected root: %s, but got: %s",
+				test.equation, test.root, root)
+		}
+	}
+}
+
+func Test_Solve_Quadratic_1(t *testing.T) {
+	tests := []struct {
+		equation string
+		answer   string
+	}{
+		{"x a = b x", FALSE},
+		{"a x y = y x b", FALSE},
+		{"x y a = b y x", FALSE},
+		{"x a b = b a x", TRUE},
+		{"a b z x x z = y a y b", TRUE},
+		{"x a y b = b y a x", TRUE},
+	}
+	for _, test := range tests {
+		var solver Solver
+		err := solver.InitWithOptions("Standard", "{a, b}", "{x, y, z}", test.equation,
+			Options{FullGraph: true, CycleRange: 2, DiscardGraph: true, GeneralSearch: true, NoPreChecks: true})
+		if err != nil {
+			t.Errorf("Test_Solve_Quadratic_1 error should be nil: %v", err)
+			continue
+		}
+		if !solver.IsQuadratic() {
+			t.Errorf("Test_Solve_Quadratic_1 failed: %s should be solved as quadratic", test.equation)
+		}
+		answer, _, err := solver.Solve()
+		if err != nil || answer != test.answer {
+			t.Errorf("Test_Solve_Quadratic_1 failed: %s: expected: %s, but got: %s, %v", test.equation, test.answer,
+				answer, err)
+		}
+		statistics := solver.GetResult().Statistics
+		if !statistics.Quadratic || statistics.CutNodes != 0 {
+			t.Errorf("Test_Solve_Quadratic_1 failed: %s: wrong statistics: %v", test.equation, statistics)
+		}
+		if answer == TRUE && !checkSolution(test.equation, solver.GetResult().Solution) {
+			t.Errorf("Test_Solve_Quadratic_1 failed: %s: wrong solution: %v", test.equation,
+				solver.GetResult().Solution)
+		}
+	}
+	var solver Solver
+	err := solver.InitWithOptions("Finite", "{a, b}", "{x}", "x a = b x", Options{DiscardGraph: true})
+	if err != nil || solver.IsQuadratic() {
+		t.Errorf("Test_Solve_Quadratic_1 failed: Finite algorithm type shouldn't solve equation as quadratic: %v", err)
+	}
+}
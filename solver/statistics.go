@@ -0,0 +1,54 @@
+package solver
+
+import (
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"time"
+)
+
+// Statistics describes the search performed by solve
+type Statistics struct {
+	// RuleNodes counts created nodes per rule kind
+	RuleNodes map[int]int
+	// HasBeenHits counts nodes repeating one of their ancestors, or any explored node for quadratic equations
+	HasBeenHits int
+	// InequalityLeaves counts FALSE leaves found by CheckInequality
+	InequalityLeaves int
+	// NoChildrenLeaves counts FALSE leaves no rule could be applied to
+	NoChildrenLeaves int
+	// CutNodes counts nodes exceeding cycle range
+	CutNodes          int
+	MaxEquationLength int
+	// FreshWords counts words generated by getWord
+	FreshWords     int
+	SubstituteTime time.Duration
+	SamenessTime   time.Duration
+	// Quadratic is set when equation was explored as quadratic, without cycle range
+	Quadratic bool
+}
+
+func newStatistics() Statistics {
+	return Statistics{RuleNodes: map[int]int{}}
+}
+
+// GetRuleNodes returns created nodes count by rule names
+func (statistics *Statistics) GetRuleNodes() map[string]int {
+	ruleNodes := map[string]int{}
+	for rule, count := range statistics.RuleNodes {
+		ruleNodes[RuleName(rule)] = count
+	}
+	return ruleNodes
+}
+
+func (solver *Solver) substitute(eq *Equation, sym *symbol.Symbol, newSymbols []symbol.Symbol) Equation {
+	start := time.Now()
+	result := eq.Substitute(sym, newSymbols)
+	solver.statistics.SubstituteTime += time.Since(start)
+	return result
+}
+
+func (solver *Solver) substituteVarsWithEmpty(eq *Equation) Equation {
+	start := time.Now()
+	result := eq.SubstituteVarsWithEmpty()
+	solver.statistics.SubstituteTime += time.Since(start)
+	return result
+}
@@ -1,17 +1,34 @@
 package solver
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+)
 
 const (
-	TRUE  = "TRUE"
-	FALSE = "FALSE"
+	TRUE   = "TRUE"
+	FALSE  = "FALSE"
+	CYCLED = "CYCLED"
+	CUT    = "CUT"
 )
 
+// Substitution describes replacement of Symbol with NewSymbols, which produced node from its parent
+type Substitution struct {
+	Symbol     symbol.Symbol
+	NewSymbols []symbol.Symbol
+}
+
 type Node struct {
-	Number   string
-	Parent   *Node
-	Children []*Node
-	Value    Equation
+	Number       string
+	Parent       *Node
+	Children     []*Node
+	Value        Equation
+	Rule         int
+	Substitution *Substitution
+	// Leaf tells why node was not explored further: TRUE, FALSE, CYCLED or CUT, it's empty for inner nodes
+	Leaf string
+	// Repeats is the ancestor equal to the CYCLED node, or any node explored before it for quadratic equations
+	Repeats *Node
 }
 
 func (node *Node) IsTree() bool {
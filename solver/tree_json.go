@@ -0,0 +1,137 @@
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const TreeEXT = ".tree.json"
+
+// TreeNode is the JSON description of the search tree node
+type TreeNode struct {
+	Number   string `json:"number"`
+	Equation string `json:"equation"`
+	// Rule is the tag of the rule which produced node from its parent
+	Rule string `json:"rule,omitempty"`
+	// Substitution is the substitution which produced node from its parent, such as x->yx
+	Substitution string `json:"substitution,omitempty"`
+	// Leaf is TRUE, FALSE, CYCLED or CUT for nodes which were not explored further
+	Leaf string `json:"leaf,omitempty"`
+	// Repeats is the number of the node equal to the CYCLED node, see Node.Repeats
+	Repeats string `json:"repeats,omitempty"`
+	// Solution is set for nodes on the path from the root to a TRUE leaf
+	Solution bool       `json:"solution,omitempty"`
+	Children []TreeNode `json:"children,omitempty"`
+}
+
+func newTreeNode(node *Node) TreeNode {
+	treeNode := TreeNode{
+		Number:   node.Number,
+		Equation: strings.TrimSpace(node.Value.String()),
+		Leaf:     node.Leaf,
+		Solution: node.Leaf == TRUE,
+	}
+	if node.Parent != nil {
+		treeNode.Rule = RuleTag(node.Rule)
+	}
+	if node.Substitution != nil {
+		treeNode.Substitution = substitutionString(&node.Substitution.Symbol, node.Substitution.NewSymbols)
+	}
+	if node.Repeats != nil {
+		treeNode.Repeats = node.Repeats.Number
+	}
+	for _, child := range node.Children {
+		childTreeNode := newTreeNode(child)
+		treeNode.Solution = treeNode.Solution || childTreeNode.Solution
+		treeNode.Children = append(treeNode.Children, childTreeNode)
+	}
+	return treeNode
+}
+
+// GetTree returns description of the tree explored by the last Solve run
+func (solver *Solver) GetTree() (TreeNode, error) {
+	if solver.tree == nil {
+		return TreeNode{}, fmt.Errorf("equation is not solved")
+	}
+	return newTreeNode(solver.tree), nil
+}
+
+// GetTreeFilename returns name of the file tree description is written to by WriteTree
+func (solver *Solver) GetTreeFilename() string {
+	return solver.dotWriter.writer.filename + TreeEXT
+}
+
+// WriteTree writes JSON description of the explored tree next to the graph description
+func (solver *Solver) WriteTree() error {
+	tree, err := solver.GetTree()
+	if err != nil {
+		return fmt.Errorf("error getting tree: %v", err)
+	}
+	file, err := os.Create(solver.GetTreeFilename())
+	if err != nil {
+		return fmt.Errorf("error creating tree file: %v", err)
+	}
+	err = json.NewEncoder(file).Encode(tree)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error writing tree file: %v", err)
+	}
+	return file.Close()
+}
+
+// ReadTree reads JSON tree description
+func ReadTree(source io.Reader) (TreeNode, error) {
+	var tree TreeNode
+	err := json.NewDecoder(source).Decode(&tree)
+	if err != nil {
+		return tree, fmt.Errorf("error decoding tree: %v", err)
+	}
+	return tree, nil
+}
+
+// TreeDOT describes tree in DOT with the same styles the solver writes graph with
+func TreeDOT(tree TreeNode) string {
+	var builder strings.Builder
+	builder.WriteString(DOTHeader)
+	writeTreeNodeDOT(&builder, tree, "")
+	builder.WriteString(legendStatement())
+	builder.WriteString(DOTFooter)
+	return builder.String()
+}
+
+func writeTreeNodeDOT(builder *strings.Builder, node TreeNode, parent string) {
+	builder.WriteString(nodeStatement(node.Number, node.Equation))
+	if parent != "" {
+		label := fmt.Sprintf("[%s]", node.Rule)
+		if node.Substitution != "" {
+			label = fmt.Sprintf("[%s] %s", node.Rule, node.Substitution)
+		}
+		builder.WriteString(edgeStatement(parent, node.Number, ruleByTag(node.Rule), label))
+	}
+	switch node.Leaf {
+	case TRUE, FALSE:
+		infoNumber := fmt.Sprintf("%s_%s", node.Leaf[:1], node.Number)
+		builder.WriteString(infoNodeStatement(infoNumber, node.Leaf))
+		builder.WriteString(infoEdgeStatement(node.Number, infoNumber))
+		if node.Leaf == TRUE {
+			builder.WriteString(solutionEdgeStatement(node.Number, infoNumber))
+		}
+	case CYCLED:
+		builder.WriteString(cycledNodeStatement(node.Number))
+		builder.WriteString(dottedEdgeStatement(node.Number, node.Repeats))
+	case CUT:
+		builder.WriteString(cutNodeStatement(node.Number))
+	}
+	if node.Solution {
+		builder.WriteString(solutionNodeStatement(node.Number))
+		if parent != "" {
+			builder.WriteString(solutionEdgeStatement(parent, node.Number))
+		}
+	}
+	for _, child := range node.Children {
+		writeTreeNodeDOT(builder, child, node.Number)
+	}
+}
@@ -0,0 +1,120 @@
+package solver
+
+import (
+	"fmt"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"time"
+)
+
+// TwoVariablesSolutions describes all solutions of equation, the sides of which are different words over
+// variables X and Y after their common prefix and suffix are removed. By Lyndon-Schutzenberger theorem
+// two words satisfying such relation are powers of a common word: X = z^i, Y = z^j, so both sides are
+// powers of z, and the equation holds if and only if XDifference * i + YDifference * j = 0
+type TwoVariablesSolutions struct {
+	X string
+	Y string
+	// XDifference and YDifference are the numbers of occurrences on the left side minus ones on the right side
+	XDifference int
+	YDifference int
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func gcd(first int, second int) int {
+	for second != 0 {
+		first, second = second, first%second
+	}
+	return first
+}
+
+// power returns z^k multiplied by coefficient
+func power(coefficient int) string {
+	if coefficient == 1 {
+		return "z^k"
+	}
+	return fmt.Sprintf("z^(%d k)", coefficient)
+}
+
+// String describes values of variables, such as x = z^(2 k), y = z^(3 k), k >= 0
+func (solutions *TwoVariablesSolutions) String() string {
+	x, y := solutions.X, solutions.Y
+	xDifference, yDifference := solutions.XDifference, solutions.YDifference
+	switch {
+	case xDifference == 0 && yDifference == 0:
+		return fmt.Sprintf("%s = z^i, %s = z^j, i, j >= 0", x, y)
+	case xDifference == 0:
+		return fmt.Sprintf("%s = any word, %s = $", x, y)
+	case yDifference == 0:
+		return fmt.Sprintf("%s = $, %s = any word", x, y)
+	case xDifference*yDifference > 0:
+		return fmt.Sprintf("%s = $, %s = $", x, y)
+	}
+	divisor := gcd(abs(xDifference), abs(yDifference))
+	return fmt.Sprintf("%s = %s, %s = %s, k >= 0", x, power(abs(yDifference)/divisor),
+		y, power(abs(xDifference)/divisor))
+}
+
+// withoutEmpty returns symbols of equation side except empty ones
+func withoutEmpty(part []symbol.Symbol) []symbol.Symbol {
+	var symbols []symbol.Symbol
+	for _, sym := range part {
+		if !symbol.IsEmpty(sym) {
+			symbols = append(symbols, sym)
+		}
+	}
+	return symbols
+}
+
+// twoVariablesSolutions returns solutions of equation, it's false unless equation has two variables
+// and the sides of equation without common prefix and suffix are different words over them
+func twoVariablesSolutions(equation *Equation) (TwoVariablesSolutions, bool) {
+	variables := equationVariables(equation)
+	if len(variables) != 2 {
+		return TwoVariablesSolutions{}, false
+	}
+	left, right := withoutEmpty(equation.leftPart), withoutEmpty(equation.rightPart)
+	for len(left) > 0 && len(right) > 0 && left[0] == right[0] {
+		left, right = left[1:], right[1:]
+	}
+	for len(left) > 0 && len(right) > 0 && left[len(left)-1] == right[len(right)-1] {
+		left, right = left[:len(left)-1], right[:len(right)-1]
+	}
+	if len(left) == 0 && len(right) == 0 {
+		return TwoVariablesSolutions{}, false
+	}
+	solutions := TwoVariablesSolutions{X: variables[0], Y: variables[1]}
+	for _, sym := range left {
+		if !symbol.IsVar(sym) {
+			return TwoVariablesSolutions{}, false
+		}
+		if sym.Value() == solutions.X {
+			solutions.XDifference++
+		} else {
+			solutions.YDifference++
+		}
+	}
+	for _, sym := range right {
+		if !symbol.IsVar(sym) {
+			return TwoVariablesSolutions{}, false
+		}
+		if sym.Value() == solutions.X {
+			solutions.XDifference--
+		} else {
+			solutions.YDifference--
+		}
+	}
+	return solutions, true
+}
+
+// solveTwoVariables answers equation with two periodic variables without exploring the tree,
+// empty variables are always a solution of it
+func (solver *Solver) solveTwoVariables(node *Node, start time.Time) {
+	solutions, _ := twoVariablesSolutions(&solver.equation)
+	solver.answerRoot(node, start, map[string][]string{})
+	solver.result.TwoVariables = &solutions
+}
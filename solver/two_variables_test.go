@@ -0,0 +1,113 @@
+package solver
+
+import (
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func Test_Solve_TwoVariables_1(t *testing.T) {
+	tests := []struct {
+		equation  string
+		solutions string
+	}{
+		{"x y = y x", "x = z^i, y = z^j, i, j >= 0"},
+		{"x x y = y y x", "x = z^k, y = z^k, k >= 0"},
+		{"x x y = y y y x", "x = z^(2 k), y = z^k, k >= 0"},
+		{"a x x x b = a y y b", "x = z^(2 k), y = z^(3 k), k >= 0"},
+		{"x y x = x x y", "x = z^i, y = z^j, i, j >= 0"},
+		{"x y y = y x", "x = any word, y = $"},
+		{"y x y = y x", "y = $, x = any word"},
+		{"x y = $", "x = $, y = $"},
+	}
+	for _, algorithmType := range []string{"Standard", "Finite"} {
+		for _, test := range tests {
+			var solver Solver
+			err := solver.InitWithOptions(algorithmType, "{a, b}", "{x, y}", test.equation, Options{DiscardGraph: true})
+			if err != nil {
+				t.Errorf("Test_Solve_TwoVariables_1 error should be nil: %v", err)
+				continue
+			}
+			answer, _, err := solver.Solve()
+			if err != nil || answer != TRUE {
+				t.Errorf("Test_Solve_TwoVariables_1 failed: %s: expected: %s, but got: %s, %v", test.equation, TRUE,
+					answer, err)
+			}
+			result := solver.GetResult()
+			if result.TwoVariables == nil || result.TwoVariables.String() != test.solutions || result.NodesCount != 1 {
+				t.Errorf("Test_Solve_TwoVariables_1 failed: %s: expected solutions: %s, but got: %v", test.equation,
+					test.solutions, result.TwoVariables)
+				continue
+			}
+			if !checkSolution(test.equation, result.Solution) {
+				t.Errorf("Test_Solve_TwoVariables_1 failed: %s: wrong solution: %v", test.equation, result.Solution)
+			}
+		}
+	}
+	for _, equation := range []string{"x a y = y a x", "x y z = z y x", "x y = y x"} {
+		var solver Solver
+		err := solver.InitWithOptions("Standard", "{a, b}", "{x, y, z}", equation,
+			Options{DiscardGraph: true, GeneralSearch: equation == "x y = y x"})
+		if err != nil {
+			t.Errorf("Test_Solve_TwoVariables_1 error should be nil: %v", err)
+			continue
+		}
+		solver.Solve()
+		if solver.GetResult().TwoVariables != nil {
+			t.Errorf("Test_Solve_TwoVariables_1 failed: %s should be solved with the tree", equation)
+		}
+	}
+}
+
+// isTwoVariablesSolution checks that values of variables are described by solutions
+func isTwoVariablesSolution(solutions *TwoVariablesSolutions, values map[string]string) bool {
+	x, y := values[solutions.X], values[solutions.Y]
+	if x+y != y+x {
+		return false
+	}
+	if x+y == "" {
+		return true
+	}
+	z := strings.Join(primitiveRoot(strings.Split(x+y, "")), "")
+	i, j := len(x)/len(z), len(y)/len(z)
+	return solutions.XDifference*i+solutions.YDifference*j == 0
+}
+
+// Test_TwoVariablesSolutions_1 compares solutions of random equations without constants with all short words
+func Test_TwoVariablesSolutions_1(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	letters := []symbol.Symbol{symbol.Var("x"), symbol.Var("y")}
+	side := func() []symbol.Symbol {
+		part := []symbol.Symbol{symbol.Empty()}
+		for length := random.Intn(7); len(part) <= length; {
+			part = append(part, letters[random.Intn(len(letters))])
+		}
+		return part
+	}
+	values := words([]string{"a", "b"}, 4)
+	for i := 0; i < 300; i++ {
+		var equation Equation
+		equation.set(side(), side())
+		solutions, ok := twoVariablesSolutions(&equation)
+		if !ok {
+			continue
+		}
+		for _, x := range values {
+			for _, y := range values {
+				assignment := map[string][]symbol.Symbol{"x": {}, "y": {}}
+				for _, constant := range x {
+					assignment["x"] = append(assignment["x"], symbol.Const(constant))
+				}
+				for _, constant := range y {
+					assignment["y"] = append(assignment["y"], symbol.Const(constant))
+				}
+				if isSolution(&equation, assignment) !=
+					isTwoVariablesSolution(&solutions, map[string]string{"x": strings.Join(x, ""), "y": strings.Join(y, "")}) {
+					t.Errorf("Test_TwoVariablesSolutions_1 failed: %s: x = %v, y = %v, but got: %s", equation.String(),
+						x, y, solutions.String())
+				}
+			}
+		}
+	}
+}
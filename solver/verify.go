@@ -0,0 +1,50 @@
+package solver
+
+import (
+	"fmt"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver/symbol"
+	"strings"
+)
+
+// substituteAssignment replaces variables of equation part with their values,
+// the result is space-separated constants or the empty symbol
+func substituteAssignment(part []symbol.Symbol, values map[string][]symbol.Symbol) string {
+	var constants []string
+	for _, sym := range part {
+		if symbol.IsVar(sym) {
+			for _, value := range values[sym.Value()] {
+				constants = append(constants, value.Value())
+			}
+		} else if symbol.IsConst(sym) {
+			constants = append(constants, sym.Value())
+		}
+	}
+	if len(constants) == 0 {
+		return symbol.Empty().Value()
+	}
+	return strings.Join(constants, " ")
+}
+
+// Verify checks that assignment of the form x = a b, y = $ is a solution of equation, every variable
+// of equation must be assigned; both sides of equation with the assignment substituted are returned
+func Verify(constantsAlph string, varsAlph string, equation string, assignment string,
+	options Options) (bool, string, string, error) {
+	constAlphabet, varsAlphabet, eq, err := parseInput(constantsAlph, varsAlph, equation, options)
+	if err != nil {
+		return false, "", "", err
+	}
+	values, err := parseAssignment(assignment, &constAlphabet, &varsAlphabet, options.Compact)
+	if err != nil {
+		return false, "", "", fmt.Errorf("error parsing assignment: %v", err)
+	}
+	for _, part := range [][]symbol.Symbol{eq.leftPart, eq.rightPart} {
+		for _, sym := range part {
+			if _, ok := values[sym.Value()]; symbol.IsVar(sym) && !ok {
+				return false, "", "", fmt.Errorf("no value for variable: %s", sym.Value())
+			}
+		}
+	}
+	left := substituteAssignment(eq.leftPart, values)
+	right := substituteAssignment(eq.rightPart, values)
+	return left == right, left, right, nil
+}
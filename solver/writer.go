@@ -2,54 +2,264 @@ package solver
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"hash/fnv"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
 )
 
 const (
-	FILENAME = "eq_graph_"
-	GraphEXT = ".dot"
-	PicEXT   = ".png"
+	FILENAME      = "eq_graph_"
+	GraphEXT      = ".dot"
+	PicEXT        = ".png"
+	GzipEXT       = ".gz"
+	PartSUFFIX    = ".part"
+	IndexFILENAME = "eq_graph_index.tsv"
+	maxNameLength = 48
 )
 
+// OutputFiles keeps files created in output directories during one run, so graphs of different inputs
+// with the same equation don't overwrite each other and index files are recreated once per run;
+// it's safe for concurrent use
+type OutputFiles struct {
+	mutex sync.Mutex
+	// usedNames keeps created filenames
+	usedNames map[string]bool
+	// indexedDirs keeps output directories which index file was already created
+	indexedDirs map[string]bool
+}
+
+func NewOutputFiles() *OutputFiles {
+	return &OutputFiles{
+		usedNames:   map[string]bool{},
+		indexedDirs: map[string]bool{},
+	}
+}
+
+// defaultOutputFiles is shared by solvers which options don't set OutputFiles, the run is the whole process then
+var defaultOutputFiles = NewOutputFiles()
+
 type Writer struct {
-	writer    *bufio.Writer
-	file      *os.File
-	filename  string
-	outputDir string
+	writer     *bufio.Writer
+	gzipWriter *gzip.Writer
+	file       *os.File
+	filename   string
+	outputDir  string
+	files      *OutputFiles
+	compress   bool
+	partSize   int64
+	// written is the number of bytes written to the current part before compression
+	written int64
+	parts   []string
+	// buffer keeps graph description written in memory
+	buffer *bytes.Buffer
+	// dropped is set when graph description kept in memory was dropped
+	dropped bool
+}
+
+// sanitizeName keeps letters and digits of any script, other runes are collapsed into underscores,
+// the result is at most maxNameLength runes long
+func sanitizeName(str string) string {
+	var builder strings.Builder
+	underscore := false
+	length := 0
+	for _, r := range str {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			underscore = false
+		} else if !underscore {
+			builder.WriteRune('_')
+			underscore = true
+		} else {
+			continue
+		}
+		length++
+		if length >= maxNameLength {
+			break
+		}
+	}
+	return strings.Trim(builder.String(), "_")
+}
+
+func hashName(mode string, eq string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(mode))
+	hash.Write([]byte{0})
+	hash.Write([]byte(eq))
+	return fmt.Sprintf("%08x", hash.Sum32())
 }
 
 func (writer *Writer) createFileName(mode string, eq string) string {
-	return fmt.Sprintf("%s%c%s%s_%s", writer.outputDir, os.PathSeparator, FILENAME, mode, eq)
+	name := fmt.Sprintf("%s%s_%s_%s", FILENAME, sanitizeName(mode), sanitizeName(eq), hashName(mode, eq))
+	path := filepath.Join(writer.outputDir, name)
+	files := writer.files
+	files.mutex.Lock()
+	defer files.mutex.Unlock()
+	uniquePath := path
+	for i := 2; files.usedNames[uniquePath]; i++ {
+		uniquePath = fmt.Sprintf("%s_%d", path, i)
+	}
+	files.usedNames[uniquePath] = true
+	return uniquePath
+}
+
+// writeIndex appends filename to equation mapping to the index file of the output directory,
+// the file is recreated on the first write of the run
+func (writer *Writer) writeIndex(mode string, eq string) error {
+	files := writer.files
+	files.mutex.Lock()
+	defer files.mutex.Unlock()
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !files.indexedDirs[writer.outputDir] {
+		flags |= os.O_TRUNC
+		files.indexedDirs[writer.outputDir] = true
+	}
+	file, err := os.OpenFile(filepath.Join(writer.outputDir, IndexFILENAME), flags, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening index file: %v", err)
+	}
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\n", filepath.Base(writer.filename), mode, eq)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error writing index file: %v", err)
+	}
+	return file.Close()
 }
 
 func (writer *Writer) GetGraphFilename() string {
+	if writer.compress {
+		return fmt.Sprintf("%s%s%s", writer.filename, GraphEXT, GzipEXT)
+	}
 	return fmt.Sprintf("%s%s", writer.filename, GraphEXT)
 }
 
+func (writer *Writer) getPartFilename(part int) string {
+	name := fmt.Sprintf("%s%s%d%s", writer.filename, PartSUFFIX, part, GraphEXT)
+	if writer.compress {
+		return name + GzipEXT
+	}
+	return name
+}
+
+// GetGraphFilenames returns names of all files graph description was written to
+func (writer *Writer) GetGraphFilenames() []string {
+	return writer.parts
+}
+
 func (writer *Writer) GetPicFilename() string {
 	return fmt.Sprintf("%s%s", writer.filename, PicEXT)
 }
 
-func (writer *Writer) Init(mode string, eq string, outputDir string) error {
+// Init creates output file, its name is unique among files of the run, when compress is set the stream
+// is gzip-compressed, when partSize is positive the stream rolls over into a new part file after partSize bytes
+// of uncompressed description
+func (writer *Writer) Init(mode string, eq string, outputDir string, files *OutputFiles, compress bool,
+	partSize int64) error {
 	var err error
-	var file *os.File
 	writer.outputDir = outputDir
+	writer.files = files
+	writer.compress = compress
+	writer.partSize = partSize
+	err = os.MkdirAll(outputDir, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating output directory: %v", err)
+	}
 	writer.filename = writer.createFileName(mode, eq)
-	file, err = os.Create(writer.GetGraphFilename())
+	err = writer.openPart()
+	if err != nil {
+		return fmt.Errorf("error opening part: %v", err)
+	}
+	err = writer.writeIndex(mode, eq)
+	if err != nil {
+		return fmt.Errorf("error writing index: %v", err)
+	}
+	return nil
+}
+
+// InitMemory makes writer keep everything written in memory, no files are created
+func (writer *Writer) InitMemory() {
+	writer.buffer = &bytes.Buffer{}
+	writer.writer = bufio.NewWriter(writer.buffer)
+}
+
+// InitDiscard makes writer drop everything written, no files are created
+func (writer *Writer) InitDiscard() {
+	writer.writer = bufio.NewWriter(ioutil.Discard)
+}
+
+// Drop drops graph description kept in memory and makes writer drop everything written after
+func (writer *Writer) Drop() {
+	writer.buffer = nil
+	writer.dropped = true
+	writer.InitDiscard()
+}
+
+func (writer *Writer) openPart() error {
+	filename := writer.GetGraphFilename()
+	if writer.partSize > 0 {
+		filename = writer.getPartFilename(len(writer.parts) + 1)
+	}
+	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("error creating file: %v", err)
 	}
+	writer.parts = append(writer.parts, filename)
 	writer.file = file
-	writer.writer = bufio.NewWriter(writer.file)
+	writer.written = 0
+	if writer.compress {
+		writer.gzipWriter = gzip.NewWriter(writer.file)
+		writer.writer = bufio.NewWriter(writer.gzipWriter)
+	} else {
+		writer.writer = bufio.NewWriter(writer.file)
+	}
+	return nil
+}
+
+func (writer *Writer) closePart() error {
+	err := writer.writer.Flush()
+	if err != nil {
+		return fmt.Errorf("error flushing to writer: %v", err)
+	}
+	if writer.file == nil {
+		return nil
+	}
+	if writer.compress {
+		err = writer.gzipWriter.Close()
+		if err != nil {
+			return fmt.Errorf("error closing gzip writer: %v", err)
+		}
+	}
+	err = writer.file.Close()
+	if err != nil {
+		return fmt.Errorf("error closing file: %v", err)
+	}
 	return nil
 }
 
+// Write writes str to the current part, parts are switched only between writes,
+// so every part ends with a complete statement
 func (writer *Writer) Write(str string) error {
+	if writer.partSize > 0 && writer.written > 0 && writer.written+int64(len(str)) > writer.partSize {
+		err := writer.closePart()
+		if err != nil {
+			return fmt.Errorf("error closing part: %v", err)
+		}
+		err = writer.openPart()
+		if err != nil {
+			return fmt.Errorf("error opening part: %v", err)
+		}
+	}
 	_, err := writer.writer.WriteString(str)
 	if err != nil {
 		return fmt.Errorf("error wriring to writer: %v", err)
 	}
+	writer.written += int64(len(str))
 	return nil
 }
 
@@ -60,3 +270,48 @@ func (writer *Writer) Flush() error {
 	}
 	return nil
 }
+
+func (writer *Writer) Close() error {
+	err := writer.closePart()
+	if err != nil {
+		return fmt.Errorf("error closing part: %v", err)
+	}
+	return nil
+}
+
+// ReadAll reads back graph description from all parts or from memory
+func (writer *Writer) ReadAll() ([]byte, error) {
+	if writer.dropped {
+		return nil, fmt.Errorf("graph description was dropped")
+	}
+	if writer.buffer != nil {
+		return writer.buffer.Bytes(), nil
+	}
+	var result []byte
+	for _, filename := range writer.parts {
+		bytes, err := ReadGraphFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("error reading part: %v", err)
+		}
+		result = append(result, bytes...)
+	}
+	return result, nil
+}
+
+// ReadGraphFile reads graph description file, decompressing it when it has gzip extension
+func ReadGraphFile(filename string) ([]byte, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %v", err)
+	}
+	defer file.Close()
+	if !strings.HasSuffix(filename, GzipEXT) {
+		return ioutil.ReadAll(file)
+	}
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("error opening gzip reader: %v", err)
+	}
+	defer gzipReader.Close()
+	return ioutil.ReadAll(gzipReader)
+}
@@ -0,0 +1,246 @@
+package solver
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_EscapeDOT_1(t *testing.T) {
+	result := escapeDOT(`a "b" \ c` + "\n")
+	expected := `a \"b\" \\ c\n`
+	if result != expected {
+		t.Errorf("Test_EscapeDOT_1 failed: expected %s, but got: %s", expected, result)
+	}
+}
+
+func Test_CreateFileName_1(t *testing.T) {
+	writer := Writer{outputDir: "../output_files", files: NewOutputFiles()}
+	first := writer.createFileName("Standard", "x \"y\" = y/x ")
+	second := writer.createFileName("Standard", "x \"y\" = y/x ")
+	if first == second {
+		t.Errorf("Test_CreateFileName_1 failed: same filenames for two inputs: %s", first)
+	}
+	expected := "../output_files/eq_graph_Standard_x_y_y_x_" + hashName("Standard", "x \"y\" = y/x ")
+	if first != expected {
+		t.Errorf("Test_CreateFileName_1 failed: expected %s, but got: %s", expected, first)
+	}
+	if second != expected+"_2" {
+		t.Errorf("Test_CreateFileName_1 failed: expected %s, but got: %s", expected+"_2", second)
+	}
+}
+
+func Test_OutputFiles_1(t *testing.T) {
+	outputDir := t.TempDir()
+	solveEquation := func(files *OutputFiles) string {
+		var solver Solver
+		err := solver.InitWithOptions("Standard", "{a, b}", "{x}", "x a = a x", Options{
+			OutputDir:   outputDir,
+			OutputFiles: files,
+		})
+		if err != nil {
+			t.Errorf("Test_OutputFiles_1 error should be nil: %v", err)
+			return ""
+		}
+		_, _, err = solver.Solve()
+		if err != nil {
+			t.Errorf("Test_OutputFiles_1 error should be nil: %v", err)
+		}
+		return solver.dotWriter.writer.GetGraphFilename()
+	}
+	readIndex := func() []string {
+		bytes, err := ReadGraphFile(filepath.Join(outputDir, IndexFILENAME))
+		if err != nil {
+			t.Errorf("Test_OutputFiles_1 error should be nil: %v", err)
+		}
+		return strings.Split(strings.TrimSpace(string(bytes)), "\n")
+	}
+	files := NewOutputFiles()
+	first, second := solveEquation(files), solveEquation(files)
+	if first == second {
+		t.Errorf("Test_OutputFiles_1 failed: same filenames for two inputs of the run: %s", first)
+	}
+	if lines := readIndex(); len(lines) != 2 {
+		t.Errorf("Test_OutputFiles_1 failed: index should have 2 lines, but got: %v", lines)
+	}
+	if third := solveEquation(NewOutputFiles()); third != first {
+		t.Errorf("Test_OutputFiles_1 failed: new run should reuse filename %s, but got: %s", first, third)
+	}
+	if lines := readIndex(); len(lines) != 1 {
+		t.Errorf("Test_OutputFiles_1 failed: index should be recreated by new run, but got: %v", lines)
+	}
+}
+
+func Test_OutputFiles_2(t *testing.T) {
+	outputDir := t.TempDir()
+	var filenames []string
+	for i := 0; i < 2; i++ {
+		var solver Solver
+		err := solver.Init("Standard", "{a, b}", "{x}", "x a = a x", false, false, 20, outputDir)
+		if err != nil {
+			t.Errorf("Test_OutputFiles_2 error should be nil: %v", err)
+			return
+		}
+		_, _, err = solver.Solve()
+		if err != nil {
+			t.Errorf("Test_OutputFiles_2 error should be nil: %v", err)
+			return
+		}
+		filenames = append(filenames, solver.dotWriter.writer.GetGraphFilename())
+	}
+	if filenames[0] == filenames[1] {
+		t.Errorf("Test_OutputFiles_2 failed: same filenames for two solvers: %s", filenames[0])
+	}
+	bytes, err := ReadGraphFile(filepath.Join(outputDir, IndexFILENAME))
+	if err != nil {
+		t.Errorf("Test_OutputFiles_2 error should be nil: %v", err)
+		return
+	}
+	for _, filename := range filenames {
+		if _, err := ReadGraphFile(filename); err != nil {
+			t.Errorf("Test_OutputFiles_2 failed: graph file should exist: %v", err)
+		}
+		line := strings.TrimSuffix(filepath.Base(filename), GraphEXT) + "\tStandard\tx a = a x \n"
+		if !strings.Contains(string(bytes), line) {
+			t.Errorf("Test_OutputFiles_2 failed: index should have line for %s, but got: %s", filename, bytes)
+		}
+	}
+}
+
+func Test_Solve_Parts_1(t *testing.T) {
+	var solver Solver
+	err := solver.InitWithOptions("Standard", "{a, b}", "{u}", "u u a = b u u", Options{
+		CycleRange: 20,
+		OutputDir:  "../output_files",
+		Gzip:       true,
+		PartSize:   256,
+		// the tree of one-variable equation is explored to get a large graph
+		GeneralSearch: true,
+		NoPreChecks:   true,
+	})
+	if err != nil {
+		t.Errorf("Test_Solve_Parts_1 error should be nil: %v", err)
+		return
+	}
+	result, _, err := solver.Solve()
+	if err != nil {
+		t.Errorf("Test_Solve_Parts_1 error should be nil: %v", err)
+		return
+	}
+	if result != cycledStr {
+		t.Errorf("Test_Solve_Parts_1 result should be: %s, but got: %s", cycledStr, result)
+	}
+	parts := solver.dotWriter.writer.GetGraphFilenames()
+	if len(parts) < 2 {
+		t.Errorf("Test_Solve_Parts_1 failed: expected several parts, but got: %d", len(parts))
+	}
+	bytes, err := solver.dotWriter.writer.ReadAll()
+	if err != nil {
+		t.Errorf("Test_Solve_Parts_1 error should be nil: %v", err)
+		return
+	}
+	if !strings.HasPrefix(string(bytes), "strict digraph word_eq {\n") || !strings.HasSuffix(string(bytes), "}") {
+		t.Errorf("Test_Solve_Parts_1 failed: parts don't form graph description")
+	}
+}
+
+func Test_Solve_GraphStyles_1(t *testing.T) {
+	var solver Solver
+	err := solver.InitWithOptions("Standard", "{a, b}", "{x}", "x b x = b x b", Options{
+		FullGraph:   true,
+		CycleRange:  3,
+		MemoryGraph: true,
+		// the tree of one-variable equation is explored to get leaves of all kinds
+		GeneralSearch: true,
+		NoPreChecks:   true,
+	})
+	if err != nil {
+		t.Errorf("Test_Solve_GraphStyles_1 error should be nil: %v", err)
+		return
+	}
+	answer, _, err := solver.Solve()
+	if err != nil || answer != TRUE {
+		t.Errorf("Test_Solve_GraphStyles_1 result should be: %s, but got: %s, %v", TRUE, answer, err)
+		return
+	}
+	graph, err := solver.GetGraph()
+	if err != nil {
+		t.Errorf("Test_Solve_GraphStyles_1 error should be nil: %v", err)
+		return
+	}
+	statements := []string{
+		// rule tag on edge label
+		`     "0" -> "07"[label="[2R] x->bx", color="#800080", fontcolor="#800080"];` + "\n",
+		// path from the root to TRUE leaf
+		`    "T_076" [label="TRUE", shape=box, style=filled, fillcolor="#98fb98"];` + "\n",
+		`     "076" -> "T_076" [color="#228b22", penwidth=2];` + "\n",
+		`    "076" [color="#228b22", penwidth=2];` + "\n",
+		`     "07" -> "076" [color="#228b22", penwidth=2];` + "\n",
+		`     "0" -> "07" [color="#228b22", penwidth=2];` + "\n",
+		`    "0" [color="#228b22", penwidth=2];` + "\n",
+		// FALSE and CUT leaves
+		`    "F_06" [label="FALSE", shape=box, style=filled, fillcolor="#ffb6c1"];` + "\n",
+		`     "06" -> "F_06";` + "\n",
+		`    "0777" [style="filled,dashed", fillcolor="#d3d3d3"];` + "\n",
+		// legend
+		"    subgraph cluster_legend {\n",
+		`        legend_cut [label="depth cutoff", shape=ellipse, style="filled,dashed", fillcolor="#d3d3d3"];` + "\n",
+		`        legend_rule_2 [label="[2R] second right rule", style=solid, color="#800080", fontcolor="#800080"];` + "\n",
+	}
+	for _, statement := range statements {
+		if !strings.Contains(string(graph), statement) {
+			t.Errorf("Test_Solve_GraphStyles_1 failed: graph should contain: %s", statement)
+		}
+	}
+	if strings.Contains(string(graph), `"06" [color="#228b22"`) {
+		t.Errorf("Test_Solve_GraphStyles_1 failed: FALSE leaf shouldn't be on solution path")
+	}
+}
+
+func Test_SanitizeName_1(t *testing.T) {
+	result := sanitizeName("α β = β α, ж")
+	expected := "α_β_β_α_ж"
+	if result != expected {
+		t.Errorf("Test_SanitizeName_1 failed: expected %s, but got: %s", expected, result)
+	}
+	long := sanitizeName(strings.Repeat("α", maxNameLength+10))
+	if len([]rune(long)) != maxNameLength {
+		t.Errorf("Test_SanitizeName_1 failed: name should be %d runes long, but got: %s", maxNameLength, long)
+	}
+}
+
+func Test_Solve_MemoryGraphNodeLimit_1(t *testing.T) {
+	for _, test := range []struct {
+		nodeLimit int
+		dropped   bool
+	}{{10, true}, {0, false}} {
+		var solver Solver
+		err := solver.InitWithOptions("Standard", "{a, b}", "{x, y, z}", "x a y z = z b y x",
+			Options{FullGraph: true, CycleRange: 10, MemoryGraph: true, MemoryGraphNodeLimit: test.nodeLimit,
+				GeneralSearch: true, NoPreChecks: true})
+		if err != nil {
+			t.Errorf("Test_Solve_MemoryGraphNodeLimit_1 error should be nil: %v", err)
+			continue
+		}
+		_, _, err = solver.Solve()
+		if err != nil {
+			t.Errorf("Test_Solve_MemoryGraphNodeLimit_1 error should be nil: %v", err)
+			continue
+		}
+		if solver.GetResult().NodesCount <= 10 {
+			t.Errorf("Test_Solve_MemoryGraphNodeLimit_1 failed: tree should have more than 10 nodes, but got: %d",
+				solver.GetResult().NodesCount)
+		}
+		if solver.IsGraphDropped() != test.dropped {
+			t.Errorf("Test_Solve_MemoryGraphNodeLimit_1 failed: node limit %d: graph dropped should be: %t",
+				test.nodeLimit, test.dropped)
+		}
+		graph, err := solver.GetGraph()
+		if test.dropped && (err == nil || graph != nil) {
+			t.Errorf("Test_Solve_MemoryGraphNodeLimit_1 failed: dropped graph should not be read")
+		}
+		if !test.dropped && (err != nil || !strings.HasSuffix(string(graph), DOTFooter)) {
+			t.Errorf("Test_Solve_MemoryGraphNodeLimit_1 failed: wrong graph in memory: %v", err)
+		}
+	}
+}
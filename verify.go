@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/logger"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/input"
+	"github.com/saskamegaprogrammist/MatiasevichWESolver/solver"
+)
+
+func runVerify(args []string) int {
+	cmd, _ := findCommand(VERIFY)
+	flagSet := newFlagSet(cmd)
+	inputs := addInputFlags(flagSet)
+	setParserOptions := addParserFlags(flagSet)
+	assignment := flagSet.String("assignment", "", "assignment of constants to variables: 'x = a b, y = $'")
+	constants := flagSet.String("constants", "", "constants alphabet, equation is read from input if -equation is not set")
+	variables := flagSet.String("variables", "", "variables alphabet")
+	equation := flagSet.String("equation", "", "equation")
+	if code, ok := parseFlags(flagSet, args); !ok {
+		return code
+	}
+	if *assignment == "" {
+		fmt.Fprintln(flagSet.Output(), "assignment is required")
+		flagSet.Usage()
+		return 2
+	}
+	var options solver.Options
+	setParserOptions(&options)
+	var problems []input.Problem
+	if *equation != "" {
+		problems = append(problems, input.Problem{Constants: *constants, Variables: *variables, Equation: *equation,
+			Implicit: *constants == "" || *variables == ""})
+	} else {
+		files, err := inputs.files()
+		if err != nil {
+			logger.Errorf("error reading input: %v", err)
+			return 1
+		}
+		for _, fileName := range files {
+			inputFile, err := open(fileName)
+			if err != nil {
+				logger.Errorf("%v", err)
+				fmt.Printf("%s: %v\n", fileName, err)
+				return 1
+			}
+			fileProblems, _, err := readProblems(inputFile, fileName, *inputs.inputFormat)
+			inputFile.Close()
+			if err != nil {
+				logger.Errorf("error reading input: %v", err)
+				fmt.Printf("%s: error reading input: %v\n", fileName, err)
+				return 1
+			}
+			problems = append(problems, fileProblems...)
+		}
+	}
+	code := 0
+	for _, problem := range problems {
+		if problem.Err != nil {
+			fmt.Printf("error reading problem: %v\n\n", problem.Err)
+			code = 1
+			continue
+		}
+		ok, left, right, err := solver.Verify(problem.Constants, problem.Variables, problem.Equation, *assignment,
+			problemOptions(problem, options))
+		if problem.Name != "" {
+			fmt.Printf("%s:\n", problem.Name)
+		}
+		fmt.Printf("%s \n", problem.Equation)
+		if err != nil {
+			fmt.Printf("error: %v\n\n", err)
+			code = 1
+			continue
+		}
+		fmt.Printf("left: %s \nright: %s \n", left, right)
+		if ok {
+			fmt.Printf("assignment is a solution\n\n")
+		} else {
+			fmt.Printf("assignment is not a solution\n\n")
+			code = 1
+		}
+	}
+	return code
+}